@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"l2.18/internal/audit"
+	"os"
+)
+
+func main() {
+	path := flag.String("file", "data/audit.log", "audit log to verify")
+	flag.Parse()
+
+	file, err := os.Open(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+		os.Exit(2)
+	}
+	defer file.Close()
+
+	last, err := audit.Verify(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Audit log is corrupted: %v\n", err)
+		os.Exit(1)
+	}
+
+	count := 0
+	if last != nil {
+		count = last.Seq
+	}
+	fmt.Printf("Audit log is intact: %d entries\n", count)
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"l2.18/internal/archive"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
 	"l2.18/internal/config"
 	"l2.18/internal/handler"
+	"l2.18/internal/idempotency"
+	"l2.18/internal/notify"
+	"l2.18/internal/reminder"
 	"l2.18/internal/repository"
 	"l2.18/internal/service"
+	"l2.18/internal/trash"
 	"l2.18/middleware"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -16,21 +25,106 @@ import (
 func main() {
 	cfg := config.Load()
 	repo := repository.New()
+	archiveRepo := repository.New()
+	userRepo := repository.NewMemoryUserRepository()
 
-	eventService := service.NewEventService(repo)
+	notifiers := notify.Multi{notify.NewLogNotifier(nil)}
+	var queues []*notify.RetryQueue
+	if cfg.WebhookURL != "" {
+		queues = append(queues, startRetryQueue(cfg,
+			notify.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookTimeout)))
+	}
+	if cfg.SMTPHost != "" {
+		templates, err := notify.NewTemplates("", "")
+		if err != nil {
+			log.Fatalf("Failed to parse email templates: %v", err)
+		}
+		queues = append(queues, startRetryQueue(cfg, notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			Timeout:  cfg.SMTPTimeout,
+		}, userRepo, templates)))
+	}
+	for _, queue := range queues {
+		notifiers = append(notifiers, queue)
+	}
+
+	firedStore, err := reminder.NewFileStore(cfg.ReminderStateFile)
+	if err != nil {
+		log.Fatalf("Failed to load reminder state: %v", err)
+	}
+	scheduler := reminder.NewScheduler(repo, notifiers, firedStore, clock.Real{}, cfg.ReminderInterval)
+	for _, queue := range queues {
+		queue.OnDrop(scheduler.Dropped)
+	}
+	go scheduler.Run(context.Background())
+
+	auditLog, err := audit.NewFileLog(cfg.AuditLogFile)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+
+	archiver := archive.NewArchiver(repo, archiveRepo, auditLog, clock.Real{}, cfg.ArchiveMaxAge)
+	go archiver.Run(context.Background(), cfg.ArchiveInterval)
+
+	purger := trash.NewPurger(repo, auditLog, clock.Real{}, cfg.TrashRetention)
+	go purger.Run(context.Background(), cfg.TrashPurgeInterval)
+
+	eventService := service.NewEventService(repo,
+		service.WithNotifier(notifiers),
+		service.WithArchive(archiveRepo),
+		service.WithUsers(userRepo),
+		service.WithCalendars(repository.NewMemoryCalendarRepository()),
+		service.WithGrants(repository.NewMemoryGrantRepository()),
+		service.WithFeeds(repository.NewMemoryFeedRepository()),
+		service.WithCategories(repository.NewMemoryCategoryRepository()),
+		service.WithRevisions(repository.NewMemoryRevisionRepository()),
+		service.WithAudit(auditLog),
+		service.WithSearch(repository.NewSearchIndex()),
+	)
+	if migrated, err := eventService.MigrateCalendars(); err != nil {
+		log.Fatalf("Failed to move events to calendars: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Moved %d events to default calendars", migrated)
+	}
 	eventHandler := handler.NewEventHandler(eventService)
+	userHandler := handler.NewUserHandler(service.NewUserService(userRepo))
+	adminHandler := handler.NewAdminHandler(archiver)
+	auditHandler := handler.NewAuditHandler(auditLog)
+	schedulingHandler := handler.NewSchedulingHandler(service.NewSchedulingService(eventService))
+
+	idempotencyStore := idempotency.NewStore(clock.Real{}, cfg.IdempotencyTTL)
+	go idempotencyStore.Run(context.Background(), time.Minute)
 
 	router := mux.NewRouter()
+	router.Use(middleware.Idempotency(idempotencyStore, handler.RequestUser))
 	eventHandler.RegisterRoutes(router)
+	userHandler.RegisterRoutes(router)
+	adminHandler.RegisterRoutes(router)
+	schedulingHandler.RegisterRoutes(router)
+	auditHandler.RegisterRoutes(router)
 
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 	}).Methods("GET")
 
-	handlerWithMiddleware := middleware.LoggingMiddleware("logs/requests.log")(router)
+	handlerWithMiddleware := middleware.LoggingMiddleware("logs/requests.log")(
+		middleware.RequestID(middleware.MaxBodySize(cfg.MaxBodyBytes)(router)),
+	)
 
 	log.Printf("Server starting on: 8081")
 	log.Printf("File logging enabled: logs/requests.log")
 	log.Fatal(http.ListenAndServe(":"+cfg.HTTPServerPort, handlerWithMiddleware))
 }
+
+// startRetryQueue puts notifier behind a retry queue running in background.
+func startRetryQueue(cfg *config.Config, notifier notify.Notifier) *notify.RetryQueue {
+	queue := notify.NewRetryQueue(notifier, clock.Real{}, cfg.NotifyQueueSize,
+		cfg.NotifyRetryAttempts, cfg.NotifyRetryBackoff)
+	go queue.Run(context.Background(), time.Second)
+	return queue
+}
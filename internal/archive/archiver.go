@@ -0,0 +1,202 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"log"
+	"sync"
+	"time"
+)
+
+// Status describes current or last archiving run.
+type Status struct {
+	Running    bool      `json:"running"`
+	Cutoff     time.Time `json:"cutoff,omitempty"`
+	Total      int       `json:"total"`
+	Moved      int       `json:"moved"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Archiver moves events older than maxAge from active repository to archive one.
+type Archiver struct {
+	active  repository.Repository
+	archive repository.Repository
+	audit   audit.Recorder
+	clock   clock.Clock
+	maxAge  time.Duration
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewArchiver creates new Archiver, archived events are recorded in audit log unless recorder is nil.
+func NewArchiver(active, archive repository.Repository, recorder audit.Recorder, clk clock.Clock, maxAge time.Duration) *Archiver {
+	return &Archiver{
+		active:  active,
+		archive: archive,
+		audit:   recorder,
+		clock:   clk,
+		maxAge:  maxAge,
+	}
+}
+
+// Run archives old events every interval until context is cancelled.
+func (a *Archiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.ArchiveOnce(ctx); err != nil {
+			log.Printf("Archiver: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start launches archiving in background, it returns false if a run is already in progress.
+func (a *Archiver) Start(ctx context.Context) (Status, bool) {
+	a.mu.Lock()
+	if a.status.Running {
+		status := a.status
+		a.mu.Unlock()
+		return status, false
+	}
+	a.begin()
+	status := a.status
+	a.mu.Unlock()
+
+	go func() {
+		if err := a.archiveOld(ctx); err != nil {
+			log.Printf("Archiver: %v", err)
+		}
+	}()
+	return status, true
+}
+
+// ArchiveOnce archives old events synchronously, skipping if another run is in progress.
+func (a *Archiver) ArchiveOnce(ctx context.Context) error {
+	a.mu.Lock()
+	if a.status.Running {
+		a.mu.Unlock()
+		return nil
+	}
+	a.begin()
+	a.mu.Unlock()
+
+	return a.archiveOld(ctx)
+}
+
+// Status returns progress of current or last run.
+func (a *Archiver) Status() Status {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.status
+}
+
+// begin resets status for a new run, mu must be held.
+func (a *Archiver) begin() {
+	now := a.clock.Now()
+	a.status = Status{
+		Running:   true,
+		Cutoff:    now.Add(-a.maxAge),
+		StartedAt: now,
+	}
+}
+
+// archiveOld moves every old event from active repository to archive.
+func (a *Archiver) archiveOld(ctx context.Context) (err error) {
+	defer func() {
+		a.mu.Lock()
+		a.status.Running = false
+		a.status.FinishedAt = a.clock.Now()
+		if err != nil {
+			a.status.Error = err.Error()
+		}
+		a.mu.Unlock()
+	}()
+
+	a.mu.Lock()
+	cutoff := a.status.Cutoff
+	a.mu.Unlock()
+
+	events, err := a.active.GetEventsRange(time.Time{}, cutoff)
+	if err != nil {
+		return fmt.Errorf("find old events: %w", err)
+	}
+
+	a.mu.Lock()
+	a.status.Total = len(events)
+	a.mu.Unlock()
+
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !event.Date.Before(cutoff) {
+			continue
+		}
+
+		moved, err := a.move(event.ID, cutoff)
+		if err != nil {
+			return err
+		}
+		if !moved {
+			continue
+		}
+
+		a.mu.Lock()
+		a.status.Moved++
+		a.mu.Unlock()
+	}
+	return nil
+}
+
+// move copies event to archive and removes it from active repository in one active transaction,
+// so the event cannot change in between. Events changed to be recent or removed since listing are skipped.
+// The event stays active, and is taken out of archive again, if it cannot be removed or audited.
+func (a *Archiver) move(eventID int, cutoff time.Time) (bool, error) {
+	moved, saved := false, false
+	err := a.active.Transaction(func(tx repository.Repository) error {
+		event, err := tx.GetEvent(eventID)
+		if err != nil || !event.Date.Before(cutoff) {
+			return nil
+		}
+		if err := a.archive.SaveEvent(event); err != nil {
+			return fmt.Errorf("archive event %d: %w", eventID, err)
+		}
+		saved = true
+		if err := tx.DeleteEvent(eventID); err != nil {
+			return fmt.Errorf("remove archived event %d: %w", eventID, err)
+		}
+		if a.audit != nil {
+			if err := a.audit.Record(audit.Entry{
+				Time:    a.clock.Now(),
+				Action:  model.RevisionArchive,
+				EventID: event.ID,
+				UserID:  event.UserID,
+				Before:  event,
+			}); err != nil {
+				return fmt.Errorf("audit archiving of event %d: %w", eventID, err)
+			}
+		}
+		moved = true
+		return nil
+	})
+	if err != nil && saved {
+		if undoErr := a.archive.DeleteEvent(eventID); undoErr != nil {
+			return false, fmt.Errorf("%w; take event %d out of archive: %v", err, eventID, undoErr)
+		}
+	}
+	return moved, err
+}
@@ -0,0 +1,176 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiver_MovesOldEvents(t *testing.T) {
+	active := repository.NewMemoryRepository()
+	archived := repository.NewMemoryRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	archiver := NewArchiver(active, archived, nil, clock.NewFake(now), 30*24*time.Hour)
+
+	old := &model.Event{UserID: 1, Date: now.AddDate(0, -2, 0), Text: "Old"}
+	recent := &model.Event{UserID: 1, Date: now.AddDate(0, 0, -3), Text: "Recent"}
+	require.NoError(t, active.CreateEvent(old))
+	require.NoError(t, active.CreateEvent(recent))
+
+	require.NoError(t, archiver.ArchiveOnce(context.Background()))
+
+	status := archiver.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, 1, status.Total)
+	assert.Equal(t, 1, status.Moved)
+	assert.Equal(t, now, status.FinishedAt)
+
+	_, err := active.GetEvent(old.ID)
+	assert.Error(t, err)
+	moved, err := archived.GetEvent(old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Old", moved.Text)
+
+	_, err = active.GetEvent(recent.ID)
+	assert.NoError(t, err)
+}
+
+func TestArchiver_StartReportsProgress(t *testing.T) {
+	active := repository.NewMemoryRepository()
+	archived := repository.NewMemoryRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	archiver := NewArchiver(active, archived, nil, clock.NewFake(now), time.Hour)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, active.CreateEvent(&model.Event{UserID: 1, Date: now.AddDate(0, 0, -i-1), Text: "Old"}))
+	}
+
+	status, started := archiver.Start(context.Background())
+	require.True(t, started)
+	assert.True(t, status.Running)
+
+	require.Eventually(t, func() bool {
+		return !archiver.Status().Running
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 5, archiver.Status().Moved)
+}
+
+// changingRepository changes stored events right after they are listed, as a concurrent writer would.
+type changingRepository struct {
+	*repository.MemoryRepository
+	change func()
+}
+
+func (r *changingRepository) GetEventsRange(start, end time.Time) ([]*model.Event, error) {
+	events, err := r.MemoryRepository.GetEventsRange(start, end)
+	r.change()
+	return events, err
+}
+
+func TestArchiver_MovesCurrentState(t *testing.T) {
+	memory := repository.NewMemoryRepository()
+	archived := repository.NewMemoryRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	edited := &model.Event{UserID: 1, Date: now.AddDate(0, -2, 0), Text: "Old"}
+	postponed := &model.Event{UserID: 1, Date: now.AddDate(0, -2, 0), Text: "Postponed"}
+	require.NoError(t, memory.CreateEvent(edited))
+	require.NoError(t, memory.CreateEvent(postponed))
+
+	active := &changingRepository{MemoryRepository: memory, change: func() {
+		require.NoError(t, memory.UpdateEvent(edited.ID, &model.Event{ID: edited.ID, UserID: 1, Date: edited.Date, Text: "Edited"}))
+		require.NoError(t, memory.UpdateEvent(postponed.ID, &model.Event{ID: postponed.ID, UserID: 1, Date: now.AddDate(0, 0, 1), Text: "Postponed"}))
+	}}
+	archiver := NewArchiver(active, archived, nil, clock.NewFake(now), 30*24*time.Hour)
+
+	require.NoError(t, archiver.ArchiveOnce(context.Background()))
+	assert.Equal(t, 1, archiver.Status().Moved)
+
+	moved, err := archived.GetEvent(edited.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited", moved.Text)
+
+	_, err = memory.GetEvent(postponed.ID)
+	assert.NoError(t, err)
+	_, err = archived.GetEvent(postponed.ID)
+	assert.Error(t, err)
+}
+
+type recordingAudit struct {
+	entries []audit.Entry
+	err     error
+}
+
+func (r *recordingAudit) Record(entries ...audit.Entry) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestArchiver_Audit(t *testing.T) {
+	active := repository.NewMemoryRepository()
+	archived := repository.NewMemoryRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := &model.Event{UserID: 1, Date: now.AddDate(0, -2, 0), Text: "Old"}
+	require.NoError(t, active.CreateEvent(old))
+
+	recorder := &recordingAudit{err: errors.New("disk full")}
+	archiver := NewArchiver(active, archived, recorder, clock.NewFake(now), 30*24*time.Hour)
+	assert.Error(t, archiver.ArchiveOnce(context.Background()))
+	_, err := active.GetEvent(old.ID)
+	assert.NoError(t, err, "event stays active when archiving cannot be audited")
+	_, err = archived.GetEvent(old.ID)
+	assert.Error(t, err, "archive copy is removed when archiving cannot be audited")
+
+	recorder.err = nil
+	require.NoError(t, archiver.ArchiveOnce(context.Background()))
+	_, err = active.GetEvent(old.ID)
+	assert.Error(t, err)
+	require.Len(t, recorder.entries, 1)
+	entry := recorder.entries[0]
+	assert.Equal(t, model.RevisionArchive, entry.Action)
+	assert.Equal(t, old.ID, entry.EventID)
+	assert.Zero(t, entry.Actor)
+	assert.Equal(t, now, entry.Time)
+}
+
+// failingDeleteRepository fails to remove events.
+type failingDeleteRepository struct {
+	*repository.MemoryRepository
+}
+
+func (r *failingDeleteRepository) DeleteEvent(id int) error {
+	return errors.New("disk full")
+}
+
+func (r *failingDeleteRepository) Transaction(fn func(tx repository.Repository) error) error {
+	return r.MemoryRepository.Transaction(func(tx repository.Repository) error {
+		return fn(&failingDeleteRepository{MemoryRepository: tx.(*repository.MemoryRepository)})
+	})
+}
+
+func TestArchiver_DeleteFailure(t *testing.T) {
+	memory := repository.NewMemoryRepository()
+	archived := repository.NewMemoryRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := &model.Event{UserID: 1, Date: now.AddDate(0, -2, 0), Text: "Old"}
+	require.NoError(t, memory.CreateEvent(old))
+
+	archiver := NewArchiver(&failingDeleteRepository{MemoryRepository: memory}, archived, nil, clock.NewFake(now), 30*24*time.Hour)
+	assert.Error(t, archiver.ArchiveOnce(context.Background()))
+
+	_, err := memory.GetEvent(old.ID)
+	assert.NoError(t, err)
+	_, err = archived.GetEvent(old.ID)
+	assert.Error(t, err, "event is not left in archive when it stays active")
+}
@@ -0,0 +1,196 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"l2.18/internal/model"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is single record of audit log. Hash covers all other fields,
+// including PrevHash, so entries form a chain.
+type Entry struct {
+	Seq       int          `json:"seq"`
+	Time      time.Time    `json:"time"`
+	Action    string       `json:"action"`
+	EventID   int          `json:"event_id"`
+	UserID    int          `json:"user_id"`
+	Actor     int          `json:"actor"`
+	IP        string       `json:"ip,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Before    *model.Event `json:"before,omitempty"`
+	After     *model.Event `json:"after,omitempty"`
+	PrevHash  string       `json:"prev_hash"`
+	Hash      string       `json:"hash"`
+}
+
+// Recorder appends entries to audit log, either all of them or none.
+type Recorder interface {
+	Record(entries ...Entry) error
+}
+
+// Filter selects entries, zero fields match everything.
+type Filter struct {
+	Actor   int
+	EventID int
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+// Matches checks if entry passes filter, To is exclusive.
+func (f Filter) Matches(entry Entry) bool {
+	if f.Actor != 0 && entry.Actor != f.Actor {
+		return false
+	}
+	if f.EventID != 0 && entry.EventID != f.EventID {
+		return false
+	}
+	if !f.From.IsZero() && entry.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// FileLog is append-only audit log in a file with one JSON entry per line.
+type FileLog struct {
+	mu       sync.Mutex
+	path     string
+	seq      int
+	lastHash string
+}
+
+// NewFileLog opens audit log, verifying existing entries to continue the chain.
+func NewFileLog(path string) (*FileLog, error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &FileLog{path: path}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	last, err := Verify(file)
+	if err != nil {
+		return nil, fmt.Errorf("audit log %s: %w", path, err)
+	}
+	if last != nil {
+		l.seq = last.Seq
+		l.lastHash = last.Hash
+	}
+	return l, nil
+}
+
+// Record numbers entries, links each to the previous one and appends them to the file in one write.
+// A failed write is cut off the file, so the log never holds part of entries.
+func (l *FileLog) Record(entries ...Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seq, lastHash := l.seq, l.lastHash
+	var data []byte
+	for _, entry := range entries {
+		seq++
+		entry.Seq = seq
+		entry.PrevHash = lastHash
+		hash, err := Hash(entry)
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+		lastHash = hash
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Truncate(info.Size())
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Truncate(info.Size())
+		return err
+	}
+
+	l.seq = seq
+	l.lastHash = lastHash
+	return nil
+}
+
+// Query returns entries matching filter, oldest first.
+func (l *FileLog) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := []Entry{}
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parse audit entry: %w", err)
+		}
+		if !filter.Matches(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Hash returns hex SHA-256 of entry with empty Hash field.
+func Hash(entry Entry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bytes"
+	"l2.18/internal/model"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeEntries(t *testing.T, path string) {
+	l, err := NewFileLog(path)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	event := &model.Event{ID: 1, UserID: 1, Date: start, Text: "Dentist"}
+	require.NoError(t, l.Record(Entry{Time: start, Action: "create", EventID: 1, UserID: 1, Actor: 1, After: event}))
+	require.NoError(t, l.Record(Entry{Time: start.Add(time.Hour), Action: "update", EventID: 1, UserID: 1, Actor: 2,
+		Before: event, After: &model.Event{ID: 1, UserID: 1, Date: start, Text: "Dentist, moved"}}))
+	require.NoError(t, l.Record(Entry{Time: start.Add(2 * time.Hour), Action: "delete", EventID: 2, UserID: 1, Actor: 1}))
+}
+
+func TestFileLog_RecordAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	writeEntries(t, path)
+
+	l, err := NewFileLog(path)
+	require.NoError(t, err, "reopened log continues the chain")
+	require.NoError(t, l.Record(Entry{Time: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), Action: "purge", EventID: 2}))
+
+	entries, err := l.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, 4, entries[3].Seq)
+	assert.Equal(t, entries[2].Hash, entries[3].PrevHash)
+
+	entries, err = l.Query(Filter{Actor: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Dentist", entries[0].Before.Text)
+
+	entries, err = l.Query(Filter{EventID: 1, From: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "update", entries[0].Action)
+
+	entries, err = l.Query(Filter{To: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "to is exclusive")
+
+	entries, err = l.Query(Filter{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeEntries(t, path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(strings.TrimSpace(string(data)), "\n")
+
+	last, err := Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 3, last.Seq)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "modified",
+			data: strings.Replace(string(data), `"actor":2`, `"actor":3`, 1),
+			want: "line 2: entry 2 was modified",
+		},
+		{
+			name: "removed",
+			data: lines[0] + lines[2],
+			want: "line 2: entry 3 follows entry 1",
+		},
+		{
+			name: "reordered",
+			data: lines[1] + lines[0],
+			want: "line 1: entry 2 follows entry 0",
+		},
+		{
+			name: "not json",
+			data: lines[0] + "garbage\n",
+			want: "line 2: invalid entry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(tests[0].data), 0600))
+	_, err = NewFileLog(path)
+	assert.Error(t, err, "tampered log is not continued")
+}
+
+func TestFileLog_RecordBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := NewFileLog(path)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Record(
+		Entry{Time: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Action: "create", EventID: 1},
+		Entry{Time: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Action: "create", EventID: 2},
+	))
+	require.NoError(t, l.Record())
+
+	entries, err := l.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[1].Seq)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	last, err := Verify(file)
+	require.NoError(t, err)
+	assert.Equal(t, 2, last.Seq)
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize limits size of single audit entry.
+const maxLineSize = 4 * 1024 * 1024
+
+// Verify checks that entries are numbered without gaps, every entry references hash
+// of the previous one and every hash matches entry's content. It returns the last entry.
+func Verify(r io.Reader) (*Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var last *Entry
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return last, fmt.Errorf("line %d: invalid entry: %w", line, err)
+		}
+
+		wantSeq, wantPrev := 1, ""
+		if last != nil {
+			wantSeq, wantPrev = last.Seq+1, last.Hash
+		}
+		if entry.Seq != wantSeq {
+			return last, fmt.Errorf("line %d: entry %d follows entry %d", line, entry.Seq, wantSeq-1)
+		}
+		if entry.PrevHash != wantPrev {
+			return last, fmt.Errorf("line %d: entry %d does not reference previous entry", line, entry.Seq)
+		}
+		hash, err := Hash(entry)
+		if err != nil {
+			return last, fmt.Errorf("line %d: %w", line, err)
+		}
+		if hash != entry.Hash {
+			return last, fmt.Errorf("line %d: entry %d was modified", line, entry.Seq)
+		}
+		last = &entry
+	}
+	if err := scanner.Err(); err != nil {
+		return last, err
+	}
+	return last, nil
+}
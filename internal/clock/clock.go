@@ -0,0 +1,51 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides current time, so background jobs can be tested without sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is a Clock backed by time.Now.
+type Real struct{}
+
+// Now returns current wall time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a Clock that only moves when told to.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates new Fake clock set to provided time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves fake time to provided moment.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves fake time forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -3,11 +3,40 @@ package config
 import (
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
+	"time"
 )
 
 // Config contains data from .env file.
 type Config struct {
 	HTTPServerPort string
+	MaxBodyBytes   int64
+
+	ReminderInterval  time.Duration
+	ReminderStateFile string
+	WebhookURL        string
+	WebhookTimeout    time.Duration
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTimeout  time.Duration
+
+	NotifyQueueSize     int
+	NotifyRetryAttempts int
+	NotifyRetryBackoff  time.Duration
+
+	ArchiveMaxAge   time.Duration
+	ArchiveInterval time.Duration
+
+	IdempotencyTTL time.Duration
+
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	AuditLogFile string
 }
 
 // Load loads .env file to config.
@@ -19,6 +48,33 @@ func Load() *Config {
 
 	return &Config{
 		HTTPServerPort: getEnvRequired("HTTP_SERVER_PORT"),
+		MaxBodyBytes:   int64(getEnvInt("MAX_BODY_BYTES", 1<<20)),
+
+		ReminderInterval:  getEnvDuration("REMINDER_INTERVAL", 30*time.Second),
+		ReminderStateFile: getEnv("REMINDER_STATE_FILE", "data/reminders_fired.json"),
+		WebhookURL:        getEnv("WEBHOOK_URL", ""),
+		WebhookTimeout:    getEnvDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 25),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "calendar@localhost"),
+		SMTPTimeout:  getEnvDuration("SMTP_TIMEOUT", 10*time.Second),
+
+		NotifyQueueSize:     getEnvInt("NOTIFY_QUEUE_SIZE", 1000),
+		NotifyRetryAttempts: getEnvInt("NOTIFY_RETRY_ATTEMPTS", 5),
+		NotifyRetryBackoff:  getEnvDuration("NOTIFY_RETRY_BACKOFF", 30*time.Second),
+
+		ArchiveMaxAge:   getEnvDuration("ARCHIVE_MAX_AGE", 365*24*time.Hour),
+		ArchiveInterval: getEnvDuration("ARCHIVE_INTERVAL", 24*time.Hour),
+
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		TrashRetention:     getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		AuditLogFile: getEnv("AUDIT_LOG_FILE", "data/audit.log"),
 	}
 }
 
@@ -30,3 +86,38 @@ func getEnvRequired(key string) string {
 	}
 	return value
 }
+
+// getEnv extracts optional data from .env, falling back to provided default.
+func getEnv(key, fallback string) string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	return value
+}
+
+// getEnvDuration extracts optional duration like "30s" from .env.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		panic("Environment variable " + key + " must be a duration: " + err.Error())
+	}
+	return duration
+}
+
+// getEnvInt extracts optional integer from .env.
+func getEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		panic("Environment variable " + key + " must be an integer: " + err.Error())
+	}
+	return number
+}
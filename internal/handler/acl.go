@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// granteeID parses {grantee} path variable.
+func granteeID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["grantee"])
+	if err != nil {
+		return 0, errors.ValidationError{
+			Field:   "grantee",
+			Message: "invalid user ID format",
+		}
+	}
+	return id, nil
+}
+
+// ListGrants returns users the calendar is shared with, only for calendar owner.
+func (h *EventHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	grants, err := h.service.GetGrants(userID, calendarID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grants)
+}
+
+// GrantAccess gives user from the path a role, from the body, in calendar owned by the user.
+func (h *EventHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	grantee, err := granteeID(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var grant model.Grant
+	if err := decodeJSONBody(r, &grant); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	grant.CalendarID = calendarID
+	grant.UserID = grantee
+
+	if err := h.service.GrantAccess(userID, &grant); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grant)
+}
+
+// RevokeAccess takes away role of user from the path, owners revoke anybody and grantees themselves.
+func (h *EventHandler) RevokeAccess(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	grantee, err := granteeID(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := h.service.RevokeAccess(userID, calendarID, grantee); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCalendarFreeBusy returns busy intervals of calendar within [start, end) without event details.
+func (h *EventHandler) GetCalendarFreeBusy(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	start, err := parseTime("start", r.URL.Query().Get("start"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	end, err := parseTime("end", r.URL.Query().Get("end"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	freeBusy, err := h.service.GetCalendarFreeBusy(userID, calendarID, start, end)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(freeBusy)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2_CalendarSharing(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/calendars", `{"name": "Team"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup", "duration": 15, "calendar_id": 2}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodPut, "/v2/users/7/calendars/2/acl/8", `{"role": "viewer"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPut, "/v2/users/7/calendars/2/acl/9", `{"role": "freebusy"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPut, "/v2/users/7/calendars/2/acl/9", `{"role": "owner"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/calendars/2/acl", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var grants []model.Grant
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &grants))
+	assert.Len(t, grants, 2)
+	rec = serve(router, http.MethodGet, "/v2/users/8/calendars/2/acl", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "only owner sees grants")
+
+	rec = serve(router, http.MethodGet, "/v2/users/8/events/1", "")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	events := getEvents(t, router, "/v2/users/8/events?start=2024-01-15&end=2024-01-16&calendar=2")
+	assert.Len(t, events, 1)
+	rec = serve(router, http.MethodPatch, "/v2/users/8/events/1", `{"text": "Retro"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "viewer cannot write")
+	rec = serve(router, http.MethodPost, "/delete_event/1?user_id=8", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "legacy delete is checked as well")
+	rec = serve(router, http.MethodPost, "/batch_events?user_id=8", `{"operations": [{"op": "delete", "id": 1}]}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "batch delete is checked as well")
+	rec = serve(router, http.MethodPost, "/delete_event/1", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "legacy delete needs the requesting user")
+
+	rec = serve(router, http.MethodGet, "/v2/users/9/events/1", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "free/busy role cannot read details")
+	rec = serve(router, http.MethodGet, "/v2/users/9/calendars/2/free_busy?start=2024-01-15&end=2024-01-16", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var freeBusy model.FreeBusy
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &freeBusy))
+	assert.Len(t, freeBusy.Busy, 1)
+
+	rec = serve(router, http.MethodGet, "/free_busy?user_id=7&requester_id=9&start=2024-01-15&end=2024-01-16", "")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodGet, "/free_busy?user_id=7&requester_id=10&start=2024-01-15&end=2024-01-16", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "no role in any calendar of the user")
+	rec = serve(router, http.MethodGet, "/free_busy?user_id=7&start=2024-01-15&end=2024-01-16", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "requester is required")
+
+	rec = serve(router, http.MethodGet, "/v2/users/10/events/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "no role hides the event")
+
+	rec = serve(router, http.MethodDelete, "/v2/users/8/calendars/2/acl/8", "")
+	require.Equal(t, http.StatusNoContent, rec.Code, "grantee leaves calendar")
+	rec = serve(router, http.MethodGet, "/v2/users/8/events/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(router, http.MethodDelete, "/v2/users/7/calendars/2/acl/8", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
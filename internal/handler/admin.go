@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"l2.18/internal/archive"
+	"net/http"
+)
+
+// AdminHandler contains maintenance operations.
+type AdminHandler struct {
+	archiver *archive.Archiver
+}
+
+// NewAdminHandler creates new copy of AdminHandler.
+func NewAdminHandler(archiver *archive.Archiver) *AdminHandler {
+	return &AdminHandler{
+		archiver: archiver,
+	}
+}
+
+// StartArchive starts archiving of old events and returns its progress.
+func (h *AdminHandler) StartArchive(w http.ResponseWriter, r *http.Request) {
+	status, started := h.archiver.Start(context.Background())
+
+	w.Header().Set("Content-Type", "application/json")
+	if started {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusConflict)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+// GetArchiveStatus returns progress of current or last archiving run.
+func (h *AdminHandler) GetArchiveStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.archiver.Status())
+}
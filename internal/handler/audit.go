@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/audit"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+// Limits of audit query page.
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditHandler serves audit log queries.
+type AuditHandler struct {
+	log *audit.FileLog
+}
+
+// NewAuditHandler creates new copy of AuditHandler.
+func NewAuditHandler(log *audit.FileLog) *AuditHandler {
+	return &AuditHandler{
+		log: log,
+	}
+}
+
+// QueryAudit returns audit entries filtered by actor, event_id and [from, to) time range.
+func (h *AuditHandler) QueryAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	entries, err := h.log.Query(filter)
+	if err != nil {
+		handleError(w, errors.InternalError{
+			Operation: "query_audit",
+			Message:   err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// parseAuditFilter parses audit query parameters.
+func parseAuditFilter(r *http.Request) (audit.Filter, error) {
+	query := r.URL.Query()
+	filter := audit.Filter{Limit: defaultAuditLimit}
+
+	for field, target := range map[string]*int{
+		"actor":    &filter.Actor,
+		"event_id": &filter.EventID,
+		"limit":    &filter.Limit,
+	} {
+		value := query.Get(field)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			return filter, errors.ValidationError{
+				Field:   field,
+				Message: field + " must be a positive integer",
+			}
+		}
+		*target = number
+	}
+	if filter.Limit > maxAuditLimit {
+		return filter, errors.ValidationError{
+			Field:   "limit",
+			Message: "limit must not exceed " + strconv.Itoa(maxAuditLimit),
+		}
+	}
+
+	var err error
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = parseTime("from", value); err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = parseTime("to", value); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
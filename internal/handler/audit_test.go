@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/audit"
+	"l2.18/internal/repository"
+	"l2.18/internal/service"
+	"l2.18/middleware"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAudit(t *testing.T) {
+	auditLog, err := audit.NewFileLog(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	eventService := service.NewEventService(repository.NewMemoryRepository(), service.WithAudit(auditLog))
+	NewEventHandler(eventService).RegisterRoutes(router)
+	NewAuditHandler(auditLog).RegisterRoutes(router)
+	server := middleware.RequestID(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/users/7/events",
+		strings.NewReader(`{"date": "2024-01-15T10:00:00Z", "text": "Standup"}`))
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "req-42", rec.Header().Get(middleware.RequestIDHeader))
+
+	rec = serve(server, http.MethodPost, "/delete_event/1?user_id=7", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get(middleware.RequestIDHeader), "request ID is generated")
+
+	rec = serve(server, http.MethodDelete, "/v2/users/7/trash/1", "")
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = serve(server, http.MethodGet, "/admin/audit?event_id=1&actor=7", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var entries []audit.Entry
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	require.Len(t, entries, 3)
+	assert.Equal(t, "create", entries[0].Action)
+	assert.Equal(t, "req-42", entries[0].RequestID)
+	assert.Equal(t, "192.0.2.1", entries[0].IP)
+	assert.Equal(t, "delete", entries[1].Action)
+	assert.Equal(t, "purge", entries[2].Action)
+	assert.Equal(t, "192.0.2.1", entries[2].IP)
+	assert.NotEmpty(t, entries[2].RequestID)
+
+	rec = serve(server, http.MethodGet, "/admin/audit?actor=8", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "[]\n", rec.Body.String())
+
+	rec = serve(server, http.MethodGet, "/admin/audit?limit=0", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/internal/service"
+	"l2.18/pkg/errors"
+	"net/http"
+)
+
+// batchRequest is body of batch endpoint.
+type batchRequest struct {
+	Operations []model.BatchOperation `json:"operations"`
+}
+
+// batchItem is result of one operation in batch response.
+type batchItem struct {
+	Index     int            `json:"index"`
+	Op        string         `json:"op"`
+	Status    int            `json:"status"`
+	Event     *model.Event   `json:"event,omitempty"`
+	Conflicts []*model.Event `json:"conflicts,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// BatchEvents applies list of create/update/delete operations on behalf of user_id.
+// mode=atomic (default) applies all or none, mode=best_effort reports result of every operation.
+func (h *EventHandler) BatchEvents(w http.ResponseWriter, r *http.Request) {
+	var atomic bool
+	switch r.URL.Query().Get("mode") {
+	case "", "atomic":
+		atomic = true
+	case "best_effort":
+	default:
+		h.handleError(w, errors.ValidationError{
+			Field:   "mode",
+			Message: "mode must be atomic or best_effort",
+		})
+		return
+	}
+
+	conflictMode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	var req batchRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	userID, err := requestUserID(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	results, err := h.actingService(r, userID).ApplyBatch(req.Operations, atomic, conflictMode)
+	if batchErr, ok := err.(service.BatchError); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(batchStatus(batchErr.Err))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": batchErr.Error(),
+			"index": batchErr.Index,
+		})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	items := make([]batchItem, len(results))
+	for i, result := range results {
+		items[i] = batchItem{
+			Index:     i,
+			Op:        result.Op,
+			Status:    http.StatusOK,
+			Event:     result.Event,
+			Conflicts: result.Conflicts,
+		}
+		if result.Op == model.BatchCreate {
+			items[i].Status = http.StatusCreated
+		}
+		if result.Err != nil {
+			items[i].Status = batchStatus(result.Err)
+			items[i].Error = result.Err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results": items,
+	})
+}
+
+// batchStatus is status code of failed batch operation, a missing event is 404.
+func batchStatus(err error) int {
+	if e, ok := err.(errors.BusinessError); ok && e.Message == "event not found" {
+		return http.StatusNotFound
+	}
+	return errorStatus(err)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchEvents(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/batch_events?mode=best_effort&user_id=1", `{"operations": [
+		{"op": "create", "event": {"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Standup"}},
+		{"op": "delete", "id": 42}
+	]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		Results []batchItem `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Results, 2)
+	assert.Equal(t, http.StatusCreated, body.Results[0].Status)
+	assert.Equal(t, 1, body.Results[0].Event.ID)
+	assert.Equal(t, http.StatusNotFound, body.Results[1].Status)
+	assert.NotEmpty(t, body.Results[1].Error)
+
+	rec = serve(router, http.MethodPost, "/batch_events?user_id=1", `{"operations": [
+		{"op": "delete", "id": 1},
+		{"op": "delete", "id": 42}
+	]}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"index":1`)
+
+	rec = serve(router, http.MethodGet, "/event/1?user_id=1", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "atomic batch is rolled back")
+
+	rec = serve(router, http.MethodPost, "/batch_events?mode=sometimes", `{"operations": []}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// calendarPathIDs parses {uid} and {cid} path variables.
+func calendarPathIDs(r *http.Request) (userID, calendarID int, err error) {
+	userID, _, err = pathIDs(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	calendarID, err = strconv.Atoi(mux.Vars(r)["cid"])
+	if err != nil {
+		return 0, 0, errors.ValidationError{
+			Field:   "cid",
+			Message: "invalid calendar ID format",
+		}
+	}
+	return userID, calendarID, nil
+}
+
+// ListCalendars returns calendars of the user, starting with the default one, followed by calendars shared with the user.
+func (h *EventHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	calendars, err := h.service.GetCalendars(userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendars)
+}
+
+// CreateCalendar creates calendar of the user.
+func (h *EventHandler) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var calendar model.Calendar
+	if err := decodeJSONBody(r, &calendar); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	if calendar.OwnerID != 0 && calendar.OwnerID != userID {
+		handleErrorV2(w, errors.ValidationError{
+			Field:   "owner_id",
+			Message: "owner_id does not match user in path",
+		})
+		return
+	}
+	calendar.ID = 0
+	calendar.OwnerID = userID
+
+	if err := h.service.CreateCalendar(&calendar); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/v2/users/%d/calendars/%d", userID, calendar.ID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// GetCalendar returns calendar the user has any role in.
+func (h *EventHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	calendar, err := h.service.GetCalendar(userID, calendarID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// UpdateCalendar replaces name, color and time zone of calendar owned by the user.
+func (h *EventHandler) UpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var calendar model.Calendar
+	if err := decodeJSONBody(r, &calendar); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	calendar.ID = calendarID
+
+	if err := h.service.UpdateCalendar(userID, &calendar); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// DeleteCalendar deletes empty calendar owned by the user.
+func (h *EventHandler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := h.service.DeleteCalendar(userID, calendarID); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
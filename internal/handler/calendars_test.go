@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2_Calendars(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/calendars",
+		`{"name": "Work", "color": "#336699", "time_zone": "Europe/Berlin"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var work model.Calendar
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &work))
+	assert.Equal(t, "/v2/users/7/calendars/2", rec.Header().Get("Location"), "default calendar is created first")
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/calendars", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var calendars []model.Calendar
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &calendars))
+	require.Len(t, calendars, 2)
+	assert.Equal(t, model.DefaultCalendarName, calendars[0].Name)
+
+	rec = serve(router, http.MethodPut, "/v2/users/7/calendars/2", `{"name": "Office"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodGet, "/v2/users/8/calendars/2", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "calendar of another user")
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup", "calendar_id": 2}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPost, "/v2/users/7/events", `{"date": "2024-01-15T12:00:00Z", "text": "Lunch"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	events := getEvents(t, router, "/v2/users/7/events?start=2024-01-15&end=2024-01-16&calendar=2")
+	require.Len(t, events, 1)
+	assert.Equal(t, "Standup", events[0].Text)
+	events = getEvents(t, router, "/events_for_day?user_id=7&date=2024-01-15&calendar=1,2")
+	assert.Len(t, events, 2)
+
+	rec = serve(router, http.MethodDelete, "/v2/users/7/calendars/2", "")
+	assert.Equal(t, http.StatusConflict, rec.Code, "calendar holds an event")
+	rec = serve(router, http.MethodDelete, "/v2/users/7/events/1", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(router, http.MethodDelete, "/v2/users/7/calendars/2", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events?start=2024-01-15&end=2024-01-16&calendar=x", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
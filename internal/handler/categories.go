@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ListCategories returns categories defined by the user.
+func (h *EventHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	categories, err := h.service.GetCategories(userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// SaveCategory creates or replaces user's category named in the path, body holds its color.
+func (h *EventHandler) SaveCategory(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var category model.Category
+	if err := decodeJSONBody(r, &category); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	category.UserID = userID
+	category.Name = mux.Vars(r)["name"]
+
+	if err := h.service.SaveCategory(&category); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory deletes user's category named in the path.
+func (h *EventHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := h.service.DeleteCategory(userID, mux.Vars(r)["name"]); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
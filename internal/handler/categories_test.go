@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2_Categories(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPut, "/v2/users/7/categories/Work", `{"color": "#336699"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodPut, "/v2/users/7/categories/home", `{"color": "blue"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/categories", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var categories []model.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &categories))
+	assert.Equal(t, []model.Category{{UserID: 7, Name: "work", Color: "#336699"}}, categories)
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup", "category": "work", "tags": ["Team"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, []string{"team"}, created.Tags)
+
+	rec = serve(router, http.MethodPost, "/v2/users/8/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup", "category": "work"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "category of another user")
+
+	rec = serve(router, http.MethodDelete, "/v2/users/7/categories/work", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(router, http.MethodDelete, "/v2/users/7/categories/work", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestTagFilter_ListEndpoints(t *testing.T) {
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	router, _ := newTestRouter(t,
+		&model.Event{UserID: 1, Date: date, Text: "Standup", Tags: []string{"work"}},
+		&model.Event{UserID: 1, Date: date.Add(time.Hour), Text: "Lunch", Tags: []string{"work", "personal"}},
+		&model.Event{UserID: 1, Date: date.Add(2 * time.Hour), Text: "Gym", Tags: []string{"personal"}},
+	)
+
+	for _, target := range []string{
+		"/events_for_day?user_id=1&date=2024-01-15&tag=work&tag=!personal",
+		"/events_for_week?user_id=1&date=2024-01-15&tag=WORK,!personal",
+		"/events_for_month?user_id=1&date=2024-01-15&tag=work&tag=!personal",
+		"/v2/users/1/events?start=2024-01-15&end=2024-01-16&tag=work&tag=!personal",
+	} {
+		events := getEvents(t, router, target)
+		require.Len(t, events, 1, target)
+		assert.Equal(t, "Standup", events[0].Text, target)
+	}
+
+	events := getEvents(t, router, "/events_for_day?user_id=1&date=2024-01-15&tag=!work")
+	require.Len(t, events, 1)
+	assert.Equal(t, "Gym", events[0].Text)
+
+	rec := serve(router, http.MethodGet, "/events_for_period?user_id=1&period=custom&start=2024-01-15&end=2024-01-15&tag=personal&expand=2024-01-15", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var period struct {
+		Counts   []model.BucketCount `json:"counts"`
+		Expanded struct {
+			Events []model.Event `json:"events"`
+		} `json:"expanded"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &period))
+	assert.Equal(t, 2, period.Counts[0].Count)
+	assert.Len(t, period.Expanded.Events, 2)
+
+	rec = serve(router, http.MethodGet, "/events_for_day?user_id=1&date=2024-01-15&tag=bad%20tag", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
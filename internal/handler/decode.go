@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMultipartMemory is how much of multipart form is kept in memory.
+const maxMultipartMemory = 1 << 20
+
+// Supported request media types.
+const (
+	mediaJSON       = "application/json"
+	mediaMergePatch = "application/merge-patch+json"
+	mediaForm       = "application/x-www-form-urlencoded"
+	mediaMultipart  = "multipart/form-data"
+)
+
+// mediaType returns media type of request body, empty if Content-Type is not set.
+func mediaType(r *http.Request) (string, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return "", nil
+	}
+	media, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", errors.UnsupportedMediaTypeError{
+			MediaType: header,
+			Supported: []string{mediaJSON, mediaForm, mediaMultipart},
+		}
+	}
+	return media, nil
+}
+
+// decodeEvent decodes event from JSON, form-encoded or multipart body.
+// Without Content-Type body is read as JSON, and empty body falls back to query string.
+func decodeEvent(r *http.Request) (*model.Event, error) {
+	media, err := mediaType(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch media {
+	case mediaJSON:
+		data, err = readBody(r)
+	case mediaForm, mediaMultipart, "":
+		if media == "" && r.ContentLength != 0 {
+			data, err = readBody(r)
+			break
+		}
+		values := queryFields(r.URL.Query())
+		if media != "" {
+			values, err = formValues(r, media)
+		}
+		if err == nil {
+			data, err = formJSON(values, false)
+		}
+	default:
+		err = errors.UnsupportedMediaTypeError{
+			MediaType: media,
+			Supported: []string{mediaJSON, mediaForm, mediaMultipart},
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var event model.Event
+	if err := decodeJSON(data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// decodePatch returns JSON Merge Patch from JSON body or from form fields present in the request.
+func decodePatch(r *http.Request) ([]byte, error) {
+	media, err := mediaType(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch media {
+	case mediaJSON, mediaMergePatch, "":
+		return readBody(r)
+	case mediaForm, mediaMultipart:
+		values, err := formValues(r, media)
+		if err != nil {
+			return nil, err
+		}
+		return formJSON(values, true)
+	default:
+		return nil, errors.UnsupportedMediaTypeError{
+			MediaType: media,
+			Supported: []string{mediaJSON, mediaMergePatch, mediaForm, mediaMultipart},
+		}
+	}
+}
+
+// readBody reads whole request body.
+func readBody(r *http.Request) ([]byte, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			return nil, errors.PayloadTooLargeError{Limit: tooLarge.Limit}
+		}
+		return nil, errors.ValidationError{
+			Field:   "body",
+			Message: "failed to read request body",
+		}
+	}
+	return data, nil
+}
+
+// decodeJSONBody strictly decodes JSON request body into v.
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(data, v)
+}
+
+// decodeJSON decodes single JSON value into v, rejecting unknown fields and trailing data.
+// Errors name the offending field or the line and column of a syntax error.
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return jsonError(data, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		line, column := position(data, decoder.InputOffset())
+		return errors.ValidationError{
+			Field:   "body",
+			Message: fmt.Sprintf("unexpected data after JSON value at line %d, column %d", line, column),
+		}
+	}
+	return nil
+}
+
+// jsonError converts decoding error to ValidationError pointing at the problem.
+func jsonError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+
+	switch {
+	case err == io.EOF:
+		return errors.ValidationError{
+			Field:   "body",
+			Message: "request body is empty",
+		}
+	case err == io.ErrUnexpectedEOF:
+		return errors.ValidationError{
+			Field:   "body",
+			Message: "unexpected end of JSON",
+		}
+	case stderrors.As(err, &syntaxErr):
+		line, column := position(data, syntaxErr.Offset)
+		return errors.ValidationError{
+			Field:   "body",
+			Message: fmt.Sprintf("invalid JSON at line %d, column %d: %s", line, column, syntaxErr.Error()),
+		}
+	case stderrors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return errors.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value),
+		}
+	case stderrors.As(err, &timeErr):
+		field := stringField(data, timeErr.Value)
+		if field == "" {
+			field = "body"
+		}
+		return errors.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("invalid time %s, use RFC 3339", timeErr.Value),
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return errors.ValidationError{
+			Field:   strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`),
+			Message: "unknown field",
+		}
+	default:
+		return errors.ValidationError{
+			Field:   "body",
+			Message: "invalid JSON format",
+		}
+	}
+}
+
+// stringField returns dotted path of the first field holding string value, as UnmarshalTypeError names fields.
+// Empty path means the value is not found in an object.
+func stringField(data []byte, value string) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var path []string
+	var objects []bool
+	expectKey := false
+	valueDone := func() {
+		if len(objects) > 0 && objects[len(objects)-1] {
+			path = path[:len(path)-1]
+			expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		switch t := token.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				objects = append(objects, t == '{')
+				expectKey = t == '{'
+			default:
+				objects = objects[:len(objects)-1]
+				valueDone()
+			}
+		case string:
+			if expectKey {
+				path = append(path, t)
+				expectKey = false
+				continue
+			}
+			if t == value {
+				return strings.Join(path, ".")
+			}
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+}
+
+// position converts byte offset in data to 1-based line and column.
+func position(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// formValues parses form body and returns its fields, query string is left to the handler.
+func formValues(r *http.Request, media string) (url.Values, error) {
+	var err error
+	if media == mediaMultipart {
+		err = r.ParseMultipartForm(maxMultipartMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			return nil, errors.PayloadTooLargeError{Limit: tooLarge.Limit}
+		}
+		return nil, errors.ValidationError{
+			Field:   "body",
+			Message: "invalid form format",
+		}
+	}
+	return r.PostForm, nil
+}
+
+// eventFields lists form fields of model.Event.
+var eventFields = []string{"id", "user_id", "calendar_id", "date", "text", "duration", "reminders", "tags", "category", "color"}
+
+// queryFields returns event fields of query string, other parameters such as conflict belong to the handler.
+func queryFields(query url.Values) url.Values {
+	values := make(url.Values)
+	for _, field := range eventFields {
+		if value, ok := query[field]; ok {
+			values[field] = value
+		}
+	}
+	return values
+}
+
+// formJSON converts form fields to JSON, so forms go through the same decoding and validation as JSON.
+// Values that do not fit the field, unknown fields and dates other than RFC 3339 are passed on as strings
+// and reported exactly as in JSON body. For patches an empty field becomes null and removes the value.
+func formJSON(values url.Values, patch bool) ([]byte, error) {
+	doc := make(map[string]interface{})
+	for field := range values {
+		value := strings.TrimSpace(values.Get(field))
+		if value == "" && field != "text" {
+			if patch {
+				doc[field] = nil
+			}
+			continue
+		}
+
+		switch field {
+		case "id", "user_id", "calendar_id", "duration":
+			doc[field] = formNumber(value)
+		case "text":
+			doc[field] = values.Get(field)
+		case "reminders":
+			reminders := []interface{}{}
+			for _, part := range parseList(values[field]) {
+				reminders = append(reminders, formNumber(part))
+			}
+			doc[field] = reminders
+		case "tags":
+			doc[field] = parseList(values[field])
+		default:
+			doc[field] = value
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// formNumber returns value as integer, or unchanged when it is not one.
+func formNumber(value string) interface{} {
+	if number, err := strconv.Atoi(value); err == nil {
+		return number
+	}
+	return value
+}
+
+// parseList splits repeated or comma-separated values, skipping empty ones.
+func parseList(values []string) []string {
+	list := []string{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, part)
+			}
+		}
+	}
+	return list
+}
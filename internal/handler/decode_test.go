@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/middleware"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithType(router http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateEvent_FormEncoded(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serveWithType(router, http.MethodPost, "/create_event", "application/x-www-form-urlencoded",
+		"user_id=1&date=2024-01-15T10:00:00Z&text=Dentist&duration=30&reminders=15&reminders=60")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var event model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+	assert.Equal(t, 1, event.UserID)
+	assert.Equal(t, "Dentist", event.Text)
+	assert.Equal(t, 30, event.Duration)
+	assert.Equal(t, []int{15, 60}, event.Reminders)
+}
+
+func TestCreateEvent_Multipart(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "1"))
+	require.NoError(t, writer.WriteField("date", "2024-01-15T10:00:00Z"))
+	require.NoError(t, writer.WriteField("text", "Dentist"))
+	require.NoError(t, writer.WriteField("reminders", "15,60"))
+	require.NoError(t, writer.Close())
+
+	rec := serveWithType(router, http.MethodPost, "/create_event", writer.FormDataContentType(), body.String())
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var event model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+	assert.Equal(t, []int{15, 60}, event.Reminders)
+}
+
+func TestCreateEvent_FormValidationMatchesJSON(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	tests := []struct {
+		name string
+		form string
+		json string
+	}{
+		{
+			name: "missing text",
+			form: "user_id=1&date=2024-01-15T00:00:00Z",
+			json: `{"user_id": 1, "date": "2024-01-15T00:00:00Z"}`,
+		},
+		{
+			name: "not an integer",
+			form: "user_id=one&date=2024-01-15T10:00:00Z&text=Dentist",
+			json: `{"user_id": "one", "date": "2024-01-15T10:00:00Z", "text": "Dentist"}`,
+		},
+		{
+			name: "date without time",
+			form: "user_id=1&date=2024-01-15&text=Dentist",
+			json: `{"user_id": 1, "date": "2024-01-15", "text": "Dentist"}`,
+		},
+		{
+			name: "unknown field",
+			form: "user_id=1&date=2024-01-15T10:00:00Z&txt=Dentist",
+			json: `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "txt": "Dentist"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := serveWithType(router, http.MethodPost, "/create_event", "application/x-www-form-urlencoded", tt.form)
+			body := serveWithType(router, http.MethodPost, "/create_event", "application/json", tt.json)
+			assert.Equal(t, http.StatusBadRequest, form.Code)
+			assert.Equal(t, body.Code, form.Code)
+			assert.Equal(t, body.Body.String(), form.Body.String())
+		})
+	}
+}
+
+func TestCreateEvent_UnsupportedMediaType(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serveWithType(router, http.MethodPost, "/create_event", "text/plain", "Dentist")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	rec = serveWithType(router, http.MethodPatch, "/events/1", "application/xml", "<event/>")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestPatchEvent_FormEncoded(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serveWithType(router, http.MethodPost, "/create_event", "application/json",
+		`{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Dentist", "reminders": [15]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serveWithType(router, http.MethodPatch, "/events/1?user_id=1", "application/x-www-form-urlencoded",
+		"text=Dentist+again&reminders=")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var event model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+	assert.Equal(t, "Dentist again", event.Text)
+	assert.Empty(t, event.Reminders)
+	assert.Equal(t, 1, event.UserID)
+}
+
+func TestCreateEvent_StrictJSON(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "unknown field",
+			body: `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "txt": "Dentist"}`,
+			want: "validation error: txt - unknown field",
+		},
+		{
+			name: "trailing data",
+			body: `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Dentist"} {}`,
+			want: "unexpected data after JSON value at line 1",
+		},
+		{
+			name: "syntax error",
+			body: "{\n  \"user_id\": 1,\n  \"text\": Dentist\n}",
+			want: "invalid JSON at line 3, column",
+		},
+		{
+			name: "type mismatch",
+			body: `{"user_id": "1", "date": "2024-01-15T10:00:00Z", "text": "Dentist"}`,
+			want: "validation error: user_id - expected int, got string",
+		},
+		{
+			name: "invalid time",
+			body: `{"user_id": 1, "date": "tomorrow", "text": "Dentist"}`,
+			want: "validation error: date - invalid time tomorrow, use RFC 3339",
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: "request body is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveWithType(router, http.MethodPost, "/create_event", "application/json", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func TestStringField(t *testing.T) {
+	assert.Equal(t, "date", stringField([]byte(`{"text": "ok", "date": "bad"}`), "bad"))
+	assert.Equal(t, "events.date", stringField(
+		[]byte(`{"events": [{"date": "2024-01-15T10:00:00Z", "tags": ["ok"]}, {"date": "bad"}]}`), "bad"))
+	assert.Equal(t, "", stringField([]byte(`["bad"]`), "bad"))
+}
+
+func TestPatchEvent_RejectsUnknownField(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/create_event",
+		`{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Dentist"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodPatch, "/events/1?user_id=1", `{"txt": "Dentist again"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "txt - unknown field")
+}
+
+func TestMaxBodySize(t *testing.T) {
+	router, _ := newTestRouter(t)
+	limited := middleware.MaxBodySize(64)(router)
+
+	body := `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "` + strings.Repeat("a", 100) + `"}`
+	rec := serve(limited, http.MethodPost, "/create_event", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/create_event", strings.NewReader(body))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	limited.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code, "limit applies while reading")
+
+	rec = serve(limited, http.MethodPost, "/create_event", `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "a"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"strings"
+)
+
+// eventETag returns strong ETag of event's JSON representation.
+func eventETag(event *model.Event) string {
+	data, _ := json.Marshal(event)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches checks If-None-Match header value against etag using weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatchesStrong checks If-Match header value against etag using strong comparison, weak tags never match.
+func etagMatchesStrong(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEventWithETag writes event with its ETag, or 304 if client already has it.
+func writeEventWithETag(w http.ResponseWriter, r *http.Request, event *model.Event) {
+	etag := eventETag(event)
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
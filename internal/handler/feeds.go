@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// feedLink is feed with the public URLs serving it.
+type feedLink struct {
+	*model.Feed
+	URL    string `json:"url"`
+	ICSURL string `json:"ics_url"`
+}
+
+// newFeedLink adds public URLs to feed.
+func newFeedLink(feed *model.Feed) feedLink {
+	return feedLink{
+		Feed:   feed,
+		URL:    "/feeds/" + feed.Token,
+		ICSURL: "/feeds/" + feed.Token + ".ics",
+	}
+}
+
+// publicCalendar is calendar published by feed, without owner's details.
+type publicCalendar struct {
+	Name     string         `json:"name"`
+	Color    string         `json:"color,omitempty"`
+	TimeZone string         `json:"time_zone,omitempty"`
+	Events   []*model.Event `json:"events"`
+}
+
+// feedPathIDs parses {uid}, {cid} and {fid} path variables.
+func feedPathIDs(r *http.Request) (userID, calendarID, feedID int, err error) {
+	userID, calendarID, err = calendarPathIDs(r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	feedID, err = strconv.Atoi(mux.Vars(r)["fid"])
+	if err != nil {
+		return 0, 0, 0, errors.ValidationError{
+			Field:   "fid",
+			Message: "invalid feed ID format",
+		}
+	}
+	return userID, calendarID, feedID, nil
+}
+
+// ListFeeds returns feed links of calendar owned by the user.
+func (h *EventHandler) ListFeeds(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	feeds, err := h.service.GetFeeds(userID, calendarID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	links := make([]feedLink, 0, len(feeds))
+	for _, feed := range feeds {
+		links = append(links, newFeedLink(feed))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// CreateFeed creates feed link of calendar owned by the user, optionally expiring at expires_at.
+func (h *EventHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, err := calendarPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var feed model.Feed
+	if r.ContentLength != 0 {
+		if err := decodeJSONBody(r, &feed); err != nil {
+			handleErrorV2(w, err)
+			return
+		}
+	}
+	feed.CalendarID = calendarID
+
+	if err := h.service.CreateFeed(userID, &feed); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/v2/users/%d/calendars/%d/feeds/%d", userID, calendarID, feed.ID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newFeedLink(&feed))
+}
+
+// RotateFeed gives feed link a new token, the old URLs stop working.
+func (h *EventHandler) RotateFeed(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, feedID, err := feedPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	feed, err := h.service.RotateFeed(userID, calendarID, feedID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newFeedLink(feed))
+}
+
+// RevokeFeed deletes feed link.
+func (h *EventHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	userID, calendarID, feedID, err := feedPathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := h.service.RevokeFeed(userID, calendarID, feedID); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFeed serves calendar published by feed token as JSON, no authentication needed.
+func (h *EventHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	calendar, events, err := h.readFeed(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	published := publicCalendar{
+		Name:     calendar.Name,
+		Color:    calendar.Color,
+		TimeZone: calendar.TimeZone,
+		Events:   make([]*model.Event, 0, len(events)),
+	}
+	for _, event := range events {
+		published.Events = append(published.Events, publicEvent(event))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(published)
+}
+
+// GetFeedICS serves calendar published by feed token as iCalendar, no authentication needed.
+func (h *EventHandler) GetFeedICS(w http.ResponseWriter, r *http.Request) {
+	calendar, events, err := h.readFeed(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write([]byte(renderICS(calendar, events, h.service.Now())))
+}
+
+// readFeed reads feed from {token} path variable within optional start and end parameters.
+func (h *EventHandler) readFeed(r *http.Request) (*model.Calendar, []*model.Event, error) {
+	var start, end time.Time
+	var err error
+	if value := r.URL.Query().Get("start"); value != "" {
+		if start, err = parseTime("start", value); err != nil {
+			return nil, nil, err
+		}
+	}
+	if value := r.URL.Query().Get("end"); value != "" {
+		if end, err = parseTime("end", value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return h.service.ReadFeed(mux.Vars(r)["token"], start, end)
+}
+
+// publicEvent copies event without owner and reminders.
+func publicEvent(event *model.Event) *model.Event {
+	published := *event
+	published.UserID = 0
+	published.Reminders = nil
+	return &published
+}
+
+// renderICS writes calendar with its events as iCalendar, times are in UTC.
+func renderICS(calendar *model.Calendar, events []*model.Event, now time.Time) string {
+	const stamp = "20060102T150405Z"
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICS(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//l2.18//calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("X-WR-CALNAME", escapeICS(calendar.Name))
+	if calendar.TimeZone != "" {
+		line("X-WR-TIMEZONE", calendar.TimeZone)
+	}
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("event-%d@l2.18", event.ID))
+		line("DTSTAMP", now.UTC().Format(stamp))
+		line("DTSTART", event.Date.UTC().Format(stamp))
+		if event.Duration > 0 {
+			line("DTEND", event.End().UTC().Format(stamp))
+		}
+		line("SUMMARY", escapeICS(event.Text))
+		if len(event.Tags) > 0 {
+			tags := make([]string, 0, len(event.Tags))
+			for _, tag := range event.Tags {
+				tags = append(tags, escapeICS(tag))
+			}
+			line("CATEGORIES", strings.Join(tags, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// escapeICS escapes iCalendar text value.
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICS splits content line longer than 75 octets into continuation lines without breaking characters.
+func foldICS(line string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
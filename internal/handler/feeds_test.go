@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeds(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/calendars", `{"name": "On-call", "time_zone": "Europe/Berlin"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "duration": 60, "text": "Alice; backup, Bob", "calendar_id": 2, "reminders": [15]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodPost, "/v2/users/8/calendars/2/feeds", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "calendar of another user")
+	rec = serve(router, http.MethodPost, "/v2/users/7/calendars/2/feeds", `{"expires_at": "2000-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "expiry in the past")
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/calendars/2/feeds", "")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var link feedLink
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &link))
+	assert.Equal(t, "/v2/users/7/calendars/2/feeds/1", rec.Header().Get("Location"))
+
+	rec = serve(router, http.MethodGet, link.URL+"?start=2024-01-15&end=2024-01-16", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var published publicCalendar
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &published))
+	assert.Equal(t, "On-call", published.Name)
+	require.Len(t, published.Events, 1)
+	assert.Zero(t, published.Events[0].UserID)
+	assert.Empty(t, published.Events[0].Reminders)
+
+	rec = serve(router, http.MethodGet, link.ICSURL+"?start=2024-01-15&end=2024-01-16", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	ics := rec.Body.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "X-WR-TIMEZONE:Europe/Berlin\r\n")
+	assert.Contains(t, ics, "DTSTART:20240115T100000Z\r\nDTEND:20240115T110000Z\r\n")
+	assert.Contains(t, ics, `SUMMARY:Alice\; backup\, Bob`)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/calendars/2/feeds", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var links []feedLink
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &links))
+	require.Len(t, links, 1)
+	assert.Equal(t, 2, links[0].Hits)
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/calendars/2/feeds/1/rotate", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var rotated feedLink
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rotated))
+	rec = serve(router, http.MethodGet, link.URL, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "old token")
+	rec = serve(router, http.MethodGet, rotated.ICSURL, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(router, http.MethodDelete, "/v2/users/7/calendars/2/feeds/1", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(router, http.MethodGet, rotated.URL, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "revoked")
+}
+
+func TestFoldICS(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("ü", 50)
+	folded := foldICS(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(part), 75)
+	}
+	assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// gridWeeks is the number of rows in a month grid, enough for any month.
+const gridWeeks = 6
+
+// gridCell is a single day of a month grid.
+type gridCell struct {
+	Date    string         `json:"date"`
+	InMonth bool           `json:"in_month"`
+	Today   bool           `json:"today"`
+	Count   int            `json:"count"`
+	More    int            `json:"more,omitempty"`
+	Events  []*model.Event `json:"events"`
+}
+
+// GetCalendarGrid returns month grid of 6 weeks with events grouped per day.
+// week_start chooses first column, max_events limits events per cell and reports the rest as more.
+func (h *EventHandler) GetCalendarGrid(w http.ResponseWriter, r *http.Request) {
+	q, err := h.parseCalendarQuery(r, false)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	firstDay, err := parseWeekday(r.URL.Query().Get("week_start"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	maxEvents := 0
+	if value := r.URL.Query().Get("max_events"); value != "" {
+		maxEvents, err = strconv.Atoi(value)
+		if err != nil || maxEvents < 0 {
+			h.handleError(w, errors.ValidationError{
+				Field:   "max_events",
+				Message: "max_events must be a non-negative number",
+			})
+			return
+		}
+	}
+
+	monthStart, monthEnd, err := monthWindow(nil, q.date)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	gridStart := startOfWeek(monthStart, firstDay)
+	gridEnd := gridStart.AddDate(0, 0, gridWeeks*7)
+
+	svc, err := h.readService(r, q.userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	userEvents, err := svc.GetEventsMonth(gridStart, gridEnd.Add(-time.Nanosecond))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	if q.render {
+		userEvents = inLocation(userEvents, q.loc)
+	}
+
+	byDay := make(map[string][]*model.Event)
+	for _, event := range userEvents {
+		key := event.Date.In(q.loc).Format("2006-01-02")
+		byDay[key] = append(byDay[key], event)
+	}
+
+	today := h.service.Now().In(q.loc).Format("2006-01-02")
+	weeks := make([][]gridCell, 0, gridWeeks)
+	day := gridStart
+	for week := 0; week < gridWeeks; week++ {
+		row := make([]gridCell, 0, 7)
+		for weekday := 0; weekday < 7; weekday++ {
+			key := day.Format("2006-01-02")
+			dayEvents := byDay[key]
+			cell := gridCell{
+				Date:    key,
+				InMonth: !day.Before(monthStart) && day.Before(monthEnd),
+				Today:   key == today,
+				Count:   len(dayEvents),
+				Events:  dayEvents,
+			}
+			if maxEvents > 0 && len(dayEvents) > maxEvents {
+				cell.Events = dayEvents[:maxEvents]
+				cell.More = len(dayEvents) - maxEvents
+			}
+			if cell.Events == nil {
+				cell.Events = []*model.Event{}
+			}
+			row = append(row, cell)
+			day = day.AddDate(0, 0, 1)
+		}
+		weeks = append(weeks, row)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"month":      monthStart.Format("2006-01"),
+		"week_start": firstDay.String(),
+		"weeks":      weeks,
+	})
+}
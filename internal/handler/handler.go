@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"l2.18/internal/model"
 	"l2.18/internal/service"
 	"l2.18/pkg/errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -26,37 +29,113 @@ func NewEventHandler(service *service.EventService) *EventHandler {
 
 // handleError determines error types and returns correct ones.
 func (h *EventHandler) handleError(w http.ResponseWriter, err error) {
+	handleError(w, err)
+}
+
+// handleError writes error with status code matching its type.
+func handleError(w http.ResponseWriter, err error) {
+	status := errorStatus(err)
 	switch e := err.(type) {
+	case errors.ConflictError:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":     e.Error(),
+			"conflicts": e.Details,
+		})
+	case errors.ValidationError, errors.BusinessError, errors.InternalError, errors.ForbiddenError,
+		errors.PreconditionFailedError, errors.UnsupportedMediaTypeError, errors.PayloadTooLargeError:
+		http.Error(w, e.Error(), status)
+	default:
+		http.Error(w, "Internal server error", status)
+	}
+}
+
+// errorStatus returns HTTP status code for error type.
+func errorStatus(err error) int {
+	switch err.(type) {
+	case errors.ConflictError:
+		return http.StatusConflict
 	case errors.ValidationError:
-		http.Error(w, e.Error(), http.StatusBadRequest)
+		return http.StatusBadRequest
 	case errors.BusinessError:
-		http.Error(w, e.Error(), http.StatusServiceUnavailable)
-	case errors.InternalError:
-		http.Error(w, e.Error(), http.StatusInternalServerError)
+		return http.StatusServiceUnavailable
+	case errors.ForbiddenError:
+		return http.StatusForbidden
+	case errors.PreconditionFailedError:
+		return http.StatusPreconditionFailed
+	case errors.UnsupportedMediaTypeError:
+		return http.StatusUnsupportedMediaType
+	case errors.PayloadTooLargeError:
+		return http.StatusRequestEntityTooLarge
 	default:
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return http.StatusInternalServerError
+	}
+}
+
+// readService returns service reading as the user for read handlers, honouring include_archived flag,
+// tag and calendar filters.
+func (h *EventHandler) readService(r *http.Request, userID int) (*service.EventService, error) {
+	svc := h.actingService(r, userID)
+	if value := r.URL.Query().Get("include_archived"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.ValidationError{
+				Field:   "include_archived",
+				Message: "include_archived must be true or false",
+			}
+		}
+		if include {
+			svc = svc.IncludeArchived()
+		}
 	}
+	return filteredService(r, svc)
+}
+
+// filteredService applies filters from tag parameters, like tag=work&tag=!personal,
+// and calendar parameters, like calendar=1&calendar=2, to svc.
+func filteredService(r *http.Request, svc *service.EventService) (*service.EventService, error) {
+	filter, err := service.ParseTagFilter(r.URL.Query()["tag"])
+	if err != nil {
+		return nil, err
+	}
+	if !filter.Empty() {
+		svc = svc.Tagged(filter)
+	}
+
+	calendarIDs, err := service.ParseCalendarIDs(r.URL.Query()["calendar"])
+	if err != nil {
+		return nil, err
+	}
+	if len(calendarIDs) > 0 {
+		svc = svc.InCalendars(calendarIDs)
+	}
+	return svc, nil
 }
 
 // CreateEvent handler to create event with provided info.
 func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
-	var event model.Event
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "body",
-			Message: "invalid JSON format",
-		})
+	event, err := decodeEvent(r)
+	if err != nil {
+		h.handleError(w, err)
 		return
 	}
 
-	if err := h.service.CreateEvent(&event); err != nil {
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	conflicts, err := h.actingService(r, event.UserID).CreateEventChecked(event, mode)
+	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(event)
+	writeEventResult(w, event, conflicts, mode)
 }
 
 // UpdateEvent updates event by id with provided info.
@@ -71,29 +150,60 @@ func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var event model.Event
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "body",
-			Message: "invalid JSON format",
-		})
+	event, err := decodeEvent(r)
+	if err != nil {
+		h.handleError(w, err)
 		return
 	}
 	event.ID = id
 
-	if err := h.service.UpdateEvent(&event); err != nil {
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	conflicts, err := h.actingService(r, event.UserID).UpdateEventChecked(event, mode)
+	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(event)
+	writeEventResult(w, event, conflicts, mode)
 }
 
-// DeleteEvent deletes event by id.
-func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	id, err := strconv.Atoi(vars["id"])
+// GetEvent returns single event by id, user_id must be the event's owner.
+func (h *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handleErrorV2(w, errors.ValidationError{
+			Field:   "id",
+			Message: "invalid event ID format",
+		})
+		return
+	}
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil {
+		handleErrorV2(w, errors.ValidationError{
+			Field:   "user_id",
+			Message: "invalid user ID format",
+		})
+		return
+	}
+
+	event, err := h.service.GetUserEvent(userID, id)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	writeEventWithETag(w, r, event)
+}
+
+// PatchEvent applies JSON Merge Patch to event by id on behalf of user_id and returns merged event.
+func (h *EventHandler) PatchEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
 		h.handleError(w, errors.ValidationError{
 			Field:   "id",
@@ -102,140 +212,403 @@ func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteEvent(id); err != nil {
+	patch, err := decodePatch(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	userID, err := requestUserID(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	event, conflicts, err := h.actingService(r, userID).PatchEvent(id, patch, mode)
+	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+	writeEventResult(w, event, conflicts, mode)
 }
 
-// GetEventsForDay gets all events for a day
-func (h *EventHandler) GetEventsForDay(w http.ResponseWriter, r *http.Request) {
-	dateStr := r.URL.Query().Get("date")
-	userIDStr := r.URL.Query().Get("user_id")
+// writeEventResult writes saved event, in warn mode together with overlapping events.
+func writeEventResult(w http.ResponseWriter, event *model.Event, conflicts []*model.Event, mode service.ConflictMode) {
+	if mode != service.ConflictWarn {
+		json.NewEncoder(w).Encode(event)
+		return
+	}
+	if conflicts == nil {
+		conflicts = []*model.Event{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event":     event,
+		"conflicts": conflicts,
+	})
+}
 
-	if dateStr == "" || userIDStr == "" {
+// DeleteEvent deletes event by id on behalf of user_id.
+func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
 		h.handleError(w, errors.ValidationError{
-			Field:   "query_params",
-			Message: "date and user_id parameters are required",
+			Field:   "id",
+			Message: "invalid event ID format",
 		})
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", dateStr)
+	userID, err := requestUserID(r)
 	if err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "date",
-			Message: "invalid date format. Use YYYY-MM-DD",
-		})
+		h.handleError(w, err)
 		return
 	}
 
+	if err := h.actingService(r, userID).DeleteEvent(id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
+
+// requestUserID parses user_id query parameter naming user who makes a legacy change.
+// The service does not authenticate callers: the user is whoever the client claims to be, and calendar roles
+// are checked against that claim only. An authenticating gateway in front of the service must vouch for it.
+func requestUserID(r *http.Request) (int, error) {
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil || userID <= 0 {
+		return 0, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user_id parameter is required",
+		}
+	}
+	return userID, nil
+}
+
+// requesterID parses requester_id query parameter naming user who asks for other users' free/busy time.
+// Like user_id it is taken on trust, see requestUserID.
+func requesterID(r *http.Request) (int, error) {
+	requester, err := strconv.Atoi(r.URL.Query().Get("requester_id"))
+	if err != nil || requester <= 0 {
+		return 0, errors.ValidationError{
+			Field:   "requester_id",
+			Message: "requester_id parameter is required",
+		}
+	}
+	return requester, nil
+}
+
+// calendarQuery holds common parameters of day/week/month requests.
+type calendarQuery struct {
+	date   time.Time
+	userID int
+	loc    *time.Location
+	render bool
+}
+
+// parseCalendarQuery parses date, user_id and tz, date is midnight in caller's time zone.
+// Week requests may give ISO week instead of date.
+func (h *EventHandler) parseCalendarQuery(r *http.Request, allowWeek bool) (calendarQuery, error) {
+	query := r.URL.Query()
+	dateStr := query.Get("date")
+	weekStr, hasWeek := query.Get("week"), query.Has("week")
+	userIDStr := query.Get("user_id")
+
+	if hasWeek && !allowWeek {
+		return calendarQuery{}, errors.ValidationError{
+			Field:   "week",
+			Message: "week parameter is only supported by /events_for_week",
+		}
+	}
+	if hasWeek && dateStr != "" {
+		return calendarQuery{}, errors.ValidationError{
+			Field:   "week",
+			Message: "use either date or week parameter",
+		}
+	}
+	if (dateStr == "" && !hasWeek) || userIDStr == "" {
+		message := "date and user_id parameters are required"
+		if allowWeek {
+			message = "date or week and user_id parameters are required"
+		}
+		return calendarQuery{}, errors.ValidationError{
+			Field:   "query_params",
+			Message: message,
+		}
+	}
+
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		h.handleError(w, errors.ValidationError{
+		return calendarQuery{}, errors.ValidationError{
 			Field:   "user_id",
 			Message: "invalid user ID format",
-		})
-		return
+		}
 	}
 
-	events, err := h.service.GetEventsDay(date)
+	loc, render, err := h.location(r, userID)
 	if err != nil {
-		h.handleError(w, err)
-		return
+		return calendarQuery{}, err
 	}
 
-	var userEvents []*model.Event
-	for _, event := range events {
-		if event.UserID == userID {
-			userEvents = append(userEvents, event)
+	var date time.Time
+	if !hasWeek {
+		date, err = time.ParseInLocation("2006-01-02", dateStr, loc)
+		if err != nil {
+			return calendarQuery{}, errors.ValidationError{
+				Field:   "date",
+				Message: "invalid date format. Use YYYY-MM-DD",
+			}
+		}
+	} else {
+		date, err = parseISOWeek(weekStr, loc)
+		if err != nil {
+			return calendarQuery{}, err
 		}
 	}
 
+	return calendarQuery{
+		date:   date,
+		userID: userID,
+		loc:    loc,
+		render: render,
+	}, nil
+}
+
+// location returns time zone from tz parameter or user settings, UTC otherwise.
+// The flag tells whether the zone was chosen by the caller and responses should use it.
+func (h *EventHandler) location(r *http.Request, userID int) (*time.Location, bool, error) {
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, false, errors.ValidationError{
+				Field:   "tz",
+				Message: "unknown time zone " + tz,
+			}
+		}
+		return loc, true, nil
+	}
+
+	loc, found := h.service.UserLocation(userID)
+	return loc, found, nil
+}
+
+// writeUserEvents writes events read as the queried user, in caller's time zone if requested.
+func writeUserEvents(w http.ResponseWriter, userEvents []*model.Event, q calendarQuery) {
+	if q.render {
+		userEvents = inLocation(userEvents, q.loc)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(userEvents)
 }
 
-// GetEventsForWeek gets events for a week.
-func (h *EventHandler) GetEventsForWeek(w http.ResponseWriter, r *http.Request) {
-	dateStr := r.URL.Query().Get("date")
-	userIDStr := r.URL.Query().Get("user_id")
+// weekWindow returns [start, end) of the week containing date according to week_mode:
+// calendar (default, first day from week_start), iso (Monday based) or rolling (days around date).
+func weekWindow(query url.Values, date time.Time) (time.Time, time.Time, error) {
+	switch query.Get("week_mode") {
+	case "", "calendar":
+		firstDay, err := parseWeekday(query.Get("week_start"))
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start := startOfWeek(date, firstDay)
+		return start, start.AddDate(0, 0, 7), nil
+	case "iso":
+		start := startOfWeek(date, time.Monday)
+		return start, start.AddDate(0, 0, 7), nil
+	case "rolling":
+		return rollingWindow(query, date, 7)
+	default:
+		return time.Time{}, time.Time{}, errors.ValidationError{
+			Field:   "week_mode",
+			Message: "week_mode must be one of calendar, iso, rolling",
+		}
+	}
+}
 
-	if dateStr == "" || userIDStr == "" {
-		h.handleError(w, errors.ValidationError{
-			Field:   "query_params",
-			Message: "date and user_id parameters are required",
-		})
+// monthWindow returns [start, end) of the month containing date according to month_mode:
+// calendar (default) or rolling (days around date, 30 by default).
+func monthWindow(query url.Values, date time.Time) (time.Time, time.Time, error) {
+	switch query.Get("month_mode") {
+	case "", "calendar":
+		start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	case "rolling":
+		return rollingWindow(query, date, 30)
+	default:
+		return time.Time{}, time.Time{}, errors.ValidationError{
+			Field:   "month_mode",
+			Message: "month_mode must be one of calendar, rolling",
+		}
+	}
+}
+
+// rollingWindow returns window of N days (days parameter) centred on date.
+func rollingWindow(query url.Values, date time.Time, defaultDays int) (time.Time, time.Time, error) {
+	days := defaultDays
+	if value := query.Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 366 {
+			return time.Time{}, time.Time{}, errors.ValidationError{
+				Field:   "days",
+				Message: "days must be between 1 and 366",
+			}
+		}
+		days = parsed
+	}
+
+	start := date.AddDate(0, 0, -(days-1)/2)
+	return start, start.AddDate(0, 0, days), nil
+}
+
+// startOfWeek returns midnight of the first day of the week containing date.
+func startOfWeek(date time.Time, firstDay time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(firstDay) + 7) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, date.Location())
+}
+
+// parseWeekday parses week day name, empty value means Monday.
+func parseWeekday(value string) (time.Weekday, error) {
+	if value == "" {
+		return time.Monday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if strings.EqualFold(value, name) || strings.EqualFold(value, name[:3]) {
+			return day, nil
+		}
+	}
+	return 0, errors.ValidationError{
+		Field:   "week_start",
+		Message: "week_start must be a week day name",
+	}
+}
+
+// parseISOWeek parses ISO-8601 week like 2025-W47 into midnight of its Monday.
+func parseISOWeek(value string, loc *time.Location) (time.Time, error) {
+	invalid := errors.ValidationError{
+		Field:   "week",
+		Message: "invalid week format. Use YYYY-Www",
+	}
+
+	var year, week int
+	if _, err := fmt.Sscanf(value, "%4d-W%2d", &year, &week); err != nil || len(value) != len("2006-W01") {
+		return time.Time{}, invalid
+	}
+	if week < 1 || week > 53 {
+		return time.Time{}, invalid
+	}
+
+	// January 4th always belongs to the first ISO week.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := startOfWeek(jan4, time.Monday).AddDate(0, 0, (week-1)*7)
+	if isoYear, isoWeek := monday.ISOWeek(); isoYear != year || isoWeek != week {
+		return time.Time{}, errors.ValidationError{
+			Field:   "week",
+			Message: fmt.Sprintf("year %d has no week %d", year, week),
+		}
+	}
+	return monday, nil
+}
+
+// GetEventsForDay gets all events for a day
+func (h *EventHandler) GetEventsForDay(w http.ResponseWriter, r *http.Request) {
+	q, err := h.parseCalendarQuery(r, false)
+	if err != nil {
+		h.handleError(w, err)
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", dateStr)
+	svc, err := h.readService(r, q.userID)
 	if err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "date",
-			Message: "invalid date format. Use YYYY-MM-DD",
-		})
+		h.handleError(w, err)
 		return
 	}
 
-	userID, err := strconv.Atoi(userIDStr)
+	events, err := svc.GetEventsDay(q.date)
 	if err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "user_id",
-			Message: "invalid user ID format",
-		})
+		h.handleError(w, err)
+		return
+	}
+
+	writeUserEvents(w, events, q)
+}
+
+// GetEventsForWeek gets events for a week.
+func (h *EventHandler) GetEventsForWeek(w http.ResponseWriter, r *http.Request) {
+	q, err := h.parseCalendarQuery(r, true)
+	if err != nil {
+		h.handleError(w, err)
 		return
 	}
 
-	weekStart := date
-	for weekStart.Weekday() != time.Monday {
-		weekStart = weekStart.AddDate(0, 0, -1)
+	weekStart, weekEnd, err := weekWindow(r.URL.Query(), q.date)
+	if err != nil {
+		h.handleError(w, err)
+		return
 	}
-	weekEnd := weekStart.AddDate(0, 0, 7)
 
-	events, err := h.service.GetEventsWeek(weekStart, weekEnd)
+	svc, err := h.readService(r, q.userID)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	var userEvents []*model.Event
-	for _, event := range events {
-		if event.UserID == userID {
-			userEvents = append(userEvents, event)
-		}
+	events, err := svc.GetEventsWeek(weekStart, weekEnd.Add(-time.Nanosecond))
+	if err != nil {
+		h.handleError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(userEvents)
+	writeUserEvents(w, events, q)
 }
 
 // GetEventsForMonth get events for a month.
 func (h *EventHandler) GetEventsForMonth(w http.ResponseWriter, r *http.Request) {
-	dateStr := r.URL.Query().Get("date")
-	userIDStr := r.URL.Query().Get("user_id")
+	q, err := h.parseCalendarQuery(r, false)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
 
-	if dateStr == "" || userIDStr == "" {
-		h.handleError(w, errors.ValidationError{
-			Field:   "query_params",
-			Message: "date and user_id parameters are required",
-		})
+	monthStart, monthEnd, err := monthWindow(r.URL.Query(), q.date)
+	if err != nil {
+		h.handleError(w, err)
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", dateStr)
+	svc, err := h.readService(r, q.userID)
 	if err != nil {
-		h.handleError(w, errors.ValidationError{
-			Field:   "date",
-			Message: "invalid date format. Use YYYY-MM-DD",
-		})
+		h.handleError(w, err)
 		return
 	}
 
-	userID, err := strconv.Atoi(userIDStr)
+	events, err := svc.GetEventsMonth(monthStart, monthEnd.Add(-time.Nanosecond))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	writeUserEvents(w, events, q)
+}
+
+// GetEventsForPeriod returns per-bucket event counts for a year, quarter or custom range of days.
+// With expand=YYYY-MM-DD events of the bucket containing that day are returned as well.
+func (h *EventHandler) GetEventsForPeriod(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	userID, err := strconv.Atoi(query.Get("user_id"))
 	if err != nil {
 		h.handleError(w, errors.ValidationError{
 			Field:   "user_id",
@@ -243,23 +616,190 @@ func (h *EventHandler) GetEventsForMonth(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	loc, render, err := h.location(r, userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	bucket, err := service.ParseBucket(query.Get("bucket"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
 
-	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
-	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	var start, end time.Time
+	period := query.Get("period")
+	if period == "custom" {
+		start, err = parseLocalDate("start", query.Get("start"), loc)
+		if err == nil {
+			end, err = parseLocalDate("end", query.Get("end"), loc)
+			end = end.AddDate(0, 0, 1)
+		}
+	} else {
+		var date time.Time
+		date, err = parseLocalDate("date", query.Get("date"), loc)
+		if err == nil {
+			start, end, err = service.PeriodBounds(period, date)
+		}
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
 
-	events, err := h.service.GetEventsMonth(monthStart, monthEnd)
+	svc, err := filteredService(r, h.service)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	var userEvents []*model.Event
-	for _, event := range events {
-		if event.UserID == userID {
-			userEvents = append(userEvents, event)
+	counts, err := svc.GetPeriodCounts(userID, start, end, bucket)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"period": period,
+		"start":  start,
+		"end":    end,
+		"bucket": bucket,
+		"counts": counts,
+	}
+
+	if expandStr := query.Get("expand"); expandStr != "" {
+		expand, err := parseLocalDate("expand", expandStr, loc)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		events, err := svc.GetBucketEvents(userID, expand, bucket)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		if render {
+			events = inLocation(events, loc)
+		}
+		response["expanded"] = map[string]interface{}{
+			"start":  bucket.BucketStart(expand),
+			"events": events,
 		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(userEvents)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseLocalDate parses YYYY-MM-DD as midnight in provided time zone.
+func parseLocalDate(field, value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.ValidationError{
+			Field:   field,
+			Message: field + " parameter is required",
+		}
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, errors.ValidationError{
+			Field:   field,
+			Message: "invalid date format. Use YYYY-MM-DD",
+		}
+	}
+	return date, nil
+}
+
+// inLocation returns copies of events with dates shown in provided time zone.
+func inLocation(events []*model.Event, loc *time.Location) []*model.Event {
+	if len(events) == 0 {
+		return events
+	}
+	rendered := make([]*model.Event, 0, len(events))
+	for _, event := range events {
+		copied := *event
+		copied.Date = event.Date.In(loc)
+		rendered = append(rendered, &copied)
+	}
+	return rendered
+}
+
+// GetFreeBusy returns busy intervals of several users without event details, as seen by requester_id.
+func (h *EventHandler) GetFreeBusy(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	userIDs, err := parseUserIDs(query["user_id"])
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	start, err := parseTime("start", query.Get("start"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	end, err := parseTime("end", query.Get("end"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	requester, err := requesterID(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	freeBusy, err := h.actingService(r, requester).GetFreeBusy(userIDs, start, end)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"start": start,
+		"end":   end,
+		"users": freeBusy,
+	})
+}
+
+// parseUserIDs parses user IDs passed as repeated or comma separated values.
+func parseUserIDs(values []string) ([]int, error) {
+	var userIDs []int
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part == "" {
+				continue
+			}
+			userID, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, errors.ValidationError{
+					Field:   "user_id",
+					Message: "invalid user ID format",
+				}
+			}
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// parseTime parses RFC 3339 timestamp or YYYY-MM-DD date.
+func parseTime(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.ValidationError{
+			Field:   field,
+			Message: field + " parameter is required",
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.ValidationError{
+		Field:   field,
+		Message: "invalid time format. Use RFC 3339 or YYYY-MM-DD",
+	}
 }
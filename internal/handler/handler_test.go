@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T, events ...*model.Event) (*mux.Router, *repository.MemoryUserRepository) {
+	repo := repository.NewMemoryRepository()
+	users := repository.NewMemoryUserRepository()
+	for _, event := range events {
+		require.NoError(t, repo.CreateEvent(event))
+	}
+
+	router := mux.NewRouter()
+	NewEventHandler(service.NewEventService(repo,
+		service.WithUsers(users),
+		service.WithCalendars(repository.NewMemoryCalendarRepository()),
+		service.WithGrants(repository.NewMemoryGrantRepository()),
+		service.WithFeeds(repository.NewMemoryFeedRepository()),
+		service.WithCategories(repository.NewMemoryCategoryRepository()),
+		service.WithRevisions(repository.NewMemoryRevisionRepository()),
+		service.WithSearch(repository.NewSearchIndex()),
+	)).RegisterRoutes(router)
+	return router, users
+}
+
+func getEvents(t *testing.T, router http.Handler, target string) []model.Event {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var events []model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	return events
+}
+
+func TestGetEventsForDay_TimeZone(t *testing.T) {
+	router, users := newTestRouter(t,
+		&model.Event{UserID: 1, Date: time.Date(2024, 1, 15, 22, 30, 0, 0, time.UTC), Text: "Late UTC"},
+	)
+
+	assert.Len(t, getEvents(t, router, "/events_for_day?user_id=1&date=2024-01-15"), 1)
+
+	events := getEvents(t, router, "/events_for_day?user_id=1&date=2024-01-16&tz=Europe/Moscow")
+	require.Len(t, events, 1)
+	_, offset := events[0].Date.Zone()
+	assert.Equal(t, 3*60*60, offset, "rendered in caller's zone")
+
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, TimeZone: "Europe/Moscow"}))
+	assert.Empty(t, getEvents(t, router, "/events_for_day?user_id=1&date=2024-01-15"))
+	assert.Len(t, getEvents(t, router, "/events_for_day?user_id=1&date=2024-01-16"), 1)
+}
+
+func TestGetEventsForWeek_DSTTransition(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Clocks go forward on Sunday 2024-03-10, the week still ends at local midnight.
+	router, _ := newTestRouter(t,
+		&model.Event{UserID: 1, Date: time.Date(2024, 3, 4, 0, 30, 0, 0, newYork), Text: "Monday"},
+		&model.Event{UserID: 1, Date: time.Date(2024, 3, 10, 23, 30, 0, 0, newYork), Text: "Sunday night"},
+		&model.Event{UserID: 1, Date: time.Date(2024, 3, 11, 0, 30, 0, 0, newYork), Text: "Next Monday"},
+	)
+
+	events := getEvents(t, router, "/events_for_week?user_id=1&date=2024-03-07&tz=America/New_York")
+	require.Len(t, events, 2)
+	assert.Equal(t, "Monday", events[0].Text)
+	assert.Equal(t, "Sunday night", events[1].Text)
+}
+
+func TestGetEventsForDay_UnknownTimeZone(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events_for_day?user_id=1&date=2024-01-15&tz=Mars/Base", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWeekWindow(t *testing.T) {
+	thursday := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "calendar defaults to monday",
+			query:     "",
+			wantStart: time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "calendar starting sunday",
+			query:     "week_mode=calendar&week_start=sunday",
+			wantStart: time.Date(2025, 11, 16, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "calendar starting saturday",
+			query:     "week_start=sat",
+			wantStart: time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "iso",
+			query:     "week_mode=iso&week_start=sunday",
+			wantStart: time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "rolling seven days",
+			query:     "week_mode=rolling",
+			wantStart: time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "rolling three days",
+			query:     "week_mode=rolling&days=3",
+			wantStart: time.Date(2025, 11, 19, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			start, end, err := weekWindow(query, thursday)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestMonthWindow(t *testing.T) {
+	date := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	start, end, err := monthWindow(url.Values{}, date)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = monthWindow(url.Values{"month_mode": {"rolling"}}, date)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 30*24*time.Hour, end.Sub(start))
+
+	_, _, err = monthWindow(url.Values{"month_mode": {"lunar"}}, date)
+	assert.Error(t, err)
+}
+
+func TestParseISOWeek(t *testing.T) {
+	monday, err := parseISOWeek("2025-W47", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC), monday)
+
+	monday, err = parseISOWeek("2026-W01", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), monday)
+
+	_, err = parseISOWeek("2025-W53", time.UTC)
+	assert.Error(t, err, "2025 has 52 ISO weeks")
+
+	_, err = parseISOWeek("2025-47", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestGetEventsForWeek_ISOWeekParameter(t *testing.T) {
+	router, _ := newTestRouter(t,
+		&model.Event{UserID: 1, Date: time.Date(2025, 11, 17, 9, 0, 0, 0, time.UTC), Text: "Monday"},
+		&model.Event{UserID: 1, Date: time.Date(2025, 11, 24, 9, 0, 0, 0, time.UTC), Text: "Next Monday"},
+	)
+
+	events := getEvents(t, router, "/events_for_week?user_id=1&week=2025-W47&week_mode=iso")
+	require.Len(t, events, 1)
+	assert.Equal(t, "Monday", events[0].Text)
+}
+
+func TestCalendarQuery_WeekParameter(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/events_for_day?user_id=1&week=2025-W47", "week parameter is only supported by /events_for_week"},
+		{"/events_for_month?user_id=1&week=2025-W47", "week parameter is only supported by /events_for_week"},
+		{"/calendar_grid?user_id=1&date=2025-11-17&week=2025-W47", "week parameter is only supported by /events_for_week"},
+		{"/events_for_week?user_id=1&week=", "invalid week format. Use YYYY-Www"},
+		{"/events_for_week?user_id=1&week=2025-47", "invalid week format. Use YYYY-Www"},
+		{"/events_for_week?user_id=1&date=2025-11-17&week=2025-W47", "use either date or week parameter"},
+		{"/events_for_week?user_id=1", "date or week and user_id parameters are required"},
+		{"/events_for_day?user_id=1", "date and user_id parameters are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, tt.target, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func TestGetCalendarGrid(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, event := range []*model.Event{
+		{UserID: 1, Date: time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC), Text: "First"},
+		{UserID: 1, Date: time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC), Text: "Second"},
+		{UserID: 1, Date: time.Date(2025, 2, 3, 11, 0, 0, 0, time.UTC), Text: "Third"},
+		{UserID: 1, Date: time.Date(2025, 3, 2, 11, 0, 0, 0, time.UTC), Text: "Trailing"},
+		{UserID: 2, Date: time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC), Text: "Other user"},
+	} {
+		require.NoError(t, repo.CreateEvent(event))
+	}
+
+	now := clock.NewFake(time.Date(2025, 2, 10, 23, 0, 0, 0, time.UTC))
+	router := mux.NewRouter()
+	NewEventHandler(service.NewEventService(repo, service.WithClock(now))).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		"/calendar_grid?user_id=1&date=2025-02-14&max_events=2&tz=Europe/Moscow", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var grid struct {
+		Month string       `json:"month"`
+		Weeks [][]gridCell `json:"weeks"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &grid))
+
+	assert.Equal(t, "2025-02", grid.Month)
+	require.Len(t, grid.Weeks, 6)
+	for _, week := range grid.Weeks {
+		require.Len(t, week, 7)
+	}
+
+	first := grid.Weeks[0][0]
+	assert.Equal(t, "2025-01-27", first.Date)
+	assert.False(t, first.InMonth)
+
+	busy := grid.Weeks[1][0]
+	assert.Equal(t, "2025-02-03", busy.Date)
+	assert.True(t, busy.InMonth)
+	assert.Equal(t, 3, busy.Count)
+	assert.Equal(t, 1, busy.More)
+	assert.Len(t, busy.Events, 2)
+
+	today := grid.Weeks[2][1]
+	assert.Equal(t, "2025-02-11", today.Date, "already February 11th in Moscow")
+	assert.True(t, today.Today)
+
+	trailing := grid.Weeks[4][6]
+	assert.Equal(t, "2025-03-02", trailing.Date)
+	assert.False(t, trailing.InMonth)
+	assert.Equal(t, 1, trailing.Count)
+}
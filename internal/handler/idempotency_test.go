@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"l2.18/internal/clock"
+	"l2.18/internal/idempotency"
+	"l2.18/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithKey(router http.Handler, method, target, key, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	router, _ := newTestRouter(t)
+	router.Use(middleware.Idempotency(idempotency.NewStore(clock.Real{}, time.Hour), RequestUser))
+	body := `{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Standup"}`
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 10)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = serveWithKey(router, http.MethodPost, "/create_event", "retry-1", body)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, rec := range responses {
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.Equal(t, responses[0].Body.String(), rec.Body.String())
+	}
+	events := getEvents(t, router, "/events_for_day?date=2024-01-15&user_id=1")
+	assert.Len(t, events, 1, "duplicates create nothing")
+
+	rec := serveWithKey(router, http.MethodPost, "/create_event", "retry-1", body)
+	assert.Equal(t, "true", rec.Header().Get("Idempotent-Replayed"))
+
+	rec = serveWithKey(router, http.MethodPost, "/create_event", "retry-1",
+		`{"user_id": 1, "date": "2024-01-15T11:00:00Z", "text": "Other"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = serveWithKey(router, http.MethodPost, "/create_event", "retry-1",
+		`{"user_id": 2, "date": "2024-01-15T10:00:00Z", "text": "Standup"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code, "same key of another user")
+	assert.Empty(t, rec.Header().Get("Idempotent-Replayed"))
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// revisionNumber parses {rev} path variable.
+func revisionNumber(r *http.Request) (int, error) {
+	number, err := strconv.Atoi(mux.Vars(r)["rev"])
+	if err != nil {
+		return 0, errors.ValidationError{
+			Field:   "rev",
+			Message: "invalid revision number format",
+		}
+	}
+	return number, nil
+}
+
+// ListRevisions returns history of user's event, oldest revision first.
+func (h *EventHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetRevision returns single revision of user's event.
+func (h *EventHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	number, err := revisionNumber(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	revision, err := h.service.GetRevision(userID, eventID, number)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revision)
+}
+
+// RollbackEvent returns user's event to the state of provided revision.
+func (h *EventHandler) RollbackEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	number, err := revisionNumber(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, err := h.actingService(r, userID).RollbackEvent(userID, eventID, number)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
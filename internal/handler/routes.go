@@ -7,7 +7,68 @@ func (h *EventHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/create_event", h.CreateEvent).Methods("POST")
 	router.HandleFunc("/update_event/{id}", h.UpdateEvent).Methods("POST")
 	router.HandleFunc("/delete_event/{id}", h.DeleteEvent).Methods("POST")
+	router.HandleFunc("/batch_events", h.BatchEvents).Methods("POST")
+	router.HandleFunc("/event/{id}", h.GetEvent).Methods("GET")
+	router.HandleFunc("/events/{id}", h.PatchEvent).Methods("PATCH")
 	router.HandleFunc("/events_for_day", h.GetEventsForDay).Methods("GET")
 	router.HandleFunc("/events_for_week", h.GetEventsForWeek).Methods("GET")
 	router.HandleFunc("/events_for_month", h.GetEventsForMonth).Methods("GET")
+	router.HandleFunc("/calendar_grid", h.GetCalendarGrid).Methods("GET")
+	router.HandleFunc("/events_for_period", h.GetEventsForPeriod).Methods("GET")
+	router.HandleFunc("/free_busy", h.GetFreeBusy).Methods("GET")
+	router.HandleFunc("/search", h.SearchEvents).Methods("GET")
+	router.HandleFunc("/feeds/{token:[A-Za-z0-9_-]+}", h.GetFeed).Methods("GET")
+	router.HandleFunc("/feeds/{token:[A-Za-z0-9_-]+}.ics", h.GetFeedICS).Methods("GET")
+
+	v2 := router.PathPrefix("/v2").Subrouter()
+	v2.HandleFunc("/users/{uid}/events", h.ListUserEvents).Methods("GET")
+	v2.HandleFunc("/users/{uid}/events", h.CreateUserEvent).Methods("POST")
+	v2.HandleFunc("/users/{uid}/events/{id}", h.GetUserEvent).Methods("GET")
+	v2.HandleFunc("/users/{uid}/events/{id}", h.ReplaceUserEvent).Methods("PUT")
+	v2.HandleFunc("/users/{uid}/events/{id}", h.PatchUserEvent).Methods("PATCH")
+	v2.HandleFunc("/users/{uid}/events/{id}", h.DeleteUserEvent).Methods("DELETE")
+	v2.HandleFunc("/users/{uid}/events/{id}/revisions", h.ListRevisions).Methods("GET")
+	v2.HandleFunc("/users/{uid}/events/{id}/revisions/{rev}", h.GetRevision).Methods("GET")
+	v2.HandleFunc("/users/{uid}/events/{id}/revisions/{rev}/rollback", h.RollbackEvent).Methods("POST")
+	v2.HandleFunc("/users/{uid}/trash", h.ListTrash).Methods("GET")
+	v2.HandleFunc("/users/{uid}/trash/{id}/restore", h.RestoreTrashedEvent).Methods("POST")
+	v2.HandleFunc("/users/{uid}/trash/{id}", h.PurgeTrashedEvent).Methods("DELETE")
+	v2.HandleFunc("/users/{uid}/calendars", h.ListCalendars).Methods("GET")
+	v2.HandleFunc("/users/{uid}/calendars", h.CreateCalendar).Methods("POST")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}", h.GetCalendar).Methods("GET")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}", h.UpdateCalendar).Methods("PUT")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}", h.DeleteCalendar).Methods("DELETE")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/free_busy", h.GetCalendarFreeBusy).Methods("GET")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/acl", h.ListGrants).Methods("GET")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/acl/{grantee}", h.GrantAccess).Methods("PUT")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/acl/{grantee}", h.RevokeAccess).Methods("DELETE")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/feeds", h.ListFeeds).Methods("GET")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/feeds", h.CreateFeed).Methods("POST")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/feeds/{fid}/rotate", h.RotateFeed).Methods("POST")
+	v2.HandleFunc("/users/{uid}/calendars/{cid}/feeds/{fid}", h.RevokeFeed).Methods("DELETE")
+	v2.HandleFunc("/users/{uid}/categories", h.ListCategories).Methods("GET")
+	v2.HandleFunc("/users/{uid}/categories/{name}", h.SaveCategory).Methods("PUT")
+	v2.HandleFunc("/users/{uid}/categories/{name}", h.DeleteCategory).Methods("DELETE")
+}
+
+// RegisterRoutes registers user settings routes.
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/user/{id}", h.GetUser).Methods("GET")
+	router.HandleFunc("/update_user/{id}", h.UpdateUser).Methods("POST")
+}
+
+// RegisterRoutes registers maintenance routes.
+func (h *AdminHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/admin/archive", h.StartArchive).Methods("POST")
+	router.HandleFunc("/admin/archive", h.GetArchiveStatus).Methods("GET")
+}
+
+// RegisterRoutes registers audit log routes.
+func (h *AuditHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/admin/audit", h.QueryAudit).Methods("GET")
+}
+
+// RegisterRoutes registers meeting scheduling routes.
+func (h *SchedulingHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/find_slots", h.FindSlots).Methods("POST")
 }
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/internal/service"
+	"net/http"
+)
+
+// SchedulingHandler contains meeting slot search.
+type SchedulingHandler struct {
+	service *service.SchedulingService
+}
+
+// NewSchedulingHandler creates new copy of SchedulingHandler.
+func NewSchedulingHandler(service *service.SchedulingService) *SchedulingHandler {
+	return &SchedulingHandler{
+		service: service,
+	}
+}
+
+// FindSlots returns the earliest meeting slots where all required attendees are free, as seen by requester_id.
+func (h *SchedulingHandler) FindSlots(w http.ResponseWriter, r *http.Request) {
+	requester, err := requesterID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req model.SlotRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	slots, err := h.service.FindSlots(requester, req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slots)
+}
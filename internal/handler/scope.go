@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RequestUser returns user the request acts for: {uid} path variable,
+// user_id query parameter or user_id field of JSON or form body. Empty if none is found.
+func RequestUser(r *http.Request, body []byte) string {
+	if uid, ok := mux.Vars(r)["uid"]; ok {
+		return uid
+	}
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		return userID
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), mediaForm) {
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			return values.Get("user_id")
+		}
+		return ""
+	}
+
+	var fields struct {
+		UserID int `json:"user_id"`
+	}
+	if json.Unmarshal(body, &fields) == nil && fields.UserID != 0 {
+		return strconv.Itoa(fields.UserID)
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/service"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+// SearchEvents finds user's events by words of their text.
+func (h *EventHandler) SearchEvents(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	svc, err := h.readService(r, query.UserID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	results, err := svc.Search(query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// parseSearchQuery parses q, user_id, optional start/end range, sort and limit parameters.
+func parseSearchQuery(r *http.Request) (service.SearchQuery, error) {
+	values := r.URL.Query()
+	query := service.SearchQuery{
+		Text: values.Get("q"),
+		Sort: values.Get("sort"),
+	}
+
+	userID, err := strconv.Atoi(values.Get("user_id"))
+	if err != nil {
+		return query, errors.ValidationError{
+			Field:   "user_id",
+			Message: "invalid user ID format",
+		}
+	}
+	query.UserID = userID
+
+	if value := values.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, errors.ValidationError{
+				Field:   "limit",
+				Message: "limit must be an integer",
+			}
+		}
+	}
+	if value := values.Get("start"); value != "" {
+		if query.Start, err = parseTime("start", value); err != nil {
+			return query, err
+		}
+	}
+	if value := values.Get("end"); value != "" {
+		if query.End, err = parseTime("end", value); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
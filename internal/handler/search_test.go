@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEvents(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	for _, body := range []string{
+		`{"user_id": 1, "date": "2024-01-15T10:00:00Z", "text": "Запись к стоматологу"}`,
+		`{"user_id": 1, "date": "2024-03-15T10:00:00Z", "text": "Стоматолог, контроль"}`,
+		`{"user_id": 2, "date": "2024-01-15T10:00:00Z", "text": "Стоматолог"}`,
+	} {
+		rec := serve(router, http.MethodPost, "/create_event", body)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec := serve(router, http.MethodGet, "/search?user_id=1&q=стоматол&start=2024-02-01", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var results []model.SearchResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "Стоматолог, контроль", results[0].Text)
+	assert.Positive(t, results[0].Score)
+
+	rec = serve(router, http.MethodGet, "/search?user_id=1&q=", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serve(router, http.MethodGet, "/search?q=стоматолог", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ListTrash returns deleted events of the user that can still be restored.
+func (h *EventHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	trashed, err := h.service.GetTrash(userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trashed)
+}
+
+// RestoreTrashedEvent moves event of the user from trash back to the calendar.
+func (h *EventHandler) RestoreTrashedEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, err := h.actingService(r, userID).RestoreEvent(userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+// PurgeTrashedEvent permanently removes event of the user from trash.
+func (h *EventHandler) PurgeTrashedEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := h.actingService(r, userID).PurgeEvent(userID, eventID); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
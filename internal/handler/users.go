@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/internal/service"
+	"l2.18/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// UserHandler contains service's user settings.
+type UserHandler struct {
+	service *service.UserService
+}
+
+// NewUserHandler creates new copy of UserHandler.
+func NewUserHandler(service *service.UserService) *UserHandler {
+	return &UserHandler{
+		service: service,
+	}
+}
+
+// GetUser returns user's notification settings.
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handleError(w, errors.ValidationError{
+			Field:   "id",
+			Message: "invalid user ID format",
+		})
+		return
+	}
+
+	user, err := h.service.GetUser(id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateUser updates user's email and opt-out flag.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handleError(w, errors.ValidationError{
+			Field:   "id",
+			Message: "invalid user ID format",
+		})
+		return
+	}
+
+	var user model.User
+	if err := decodeJSONBody(r, &user); err != nil {
+		handleError(w, err)
+		return
+	}
+	user.ID = id
+
+	if err := h.service.UpdateUser(&user); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/internal/service"
+	"l2.18/middleware"
+	"l2.18/pkg/errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// handleErrorV2 is handleError for /v2 routes, where a missing event, revision, category, calendar, grant or feed is 404.
+func handleErrorV2(w http.ResponseWriter, err error) {
+	if e, ok := err.(errors.BusinessError); ok && notFound(e) {
+		http.Error(w, e.Error(), http.StatusNotFound)
+		return
+	}
+	handleError(w, err)
+}
+
+// notFound checks if business error reports a missing event, revision, category, calendar, grant or feed.
+func notFound(err errors.BusinessError) bool {
+	switch err.Message {
+	case "event not found", "revision not found", "category not found", "calendar not found", "grant not found", "feed not found":
+		return true
+	default:
+		return false
+	}
+}
+
+// pathIDs parses {uid} and, when present, {id} path variables.
+func pathIDs(r *http.Request) (userID, eventID int, err error) {
+	vars := mux.Vars(r)
+	userID, err = strconv.Atoi(vars["uid"])
+	if err != nil {
+		return 0, 0, errors.ValidationError{
+			Field:   "uid",
+			Message: "invalid user ID format",
+		}
+	}
+	if idStr, ok := vars["id"]; ok {
+		eventID, err = strconv.Atoi(idStr)
+		if err != nil {
+			return 0, 0, errors.ValidationError{
+				Field:   "id",
+				Message: "invalid event ID format",
+			}
+		}
+	}
+	return userID, eventID, nil
+}
+
+// actingService returns service acting as the user from the request's address.
+// The user is taken from the request as given, see requestUserID. Zero userID records changes without an actor.
+func (h *EventHandler) actingService(r *http.Request, userID int) *service.EventService {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return h.service.As(model.Actor{
+		UserID:    userID,
+		IP:        ip,
+		RequestID: r.Header.Get(middleware.RequestIDHeader),
+	})
+}
+
+// eventWriter checks the user from the path may see the event and returns service acting as the user.
+// The service writes the event only if it still matches If-Match header when it is stored.
+func (h *EventHandler) eventWriter(r *http.Request, userID, eventID int) (*service.EventService, error) {
+	if _, err := h.service.GetUserEvent(userID, eventID); err != nil {
+		return nil, err
+	}
+	svc := h.actingService(r, userID)
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		svc = svc.IfMatch(func(stored *model.Event) bool {
+			return etagMatchesStrong(ifMatch, eventETag(stored))
+		})
+	}
+	return svc, nil
+}
+
+// eventLocation returns canonical URL of user's event.
+func eventLocation(userID, eventID int) string {
+	return fmt.Sprintf("/v2/users/%d/events/%d", userID, eventID)
+}
+
+// ListUserEvents returns user's events overlapping [start, end).
+func (h *EventHandler) ListUserEvents(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	start, err := parseTime("start", r.URL.Query().Get("start"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	end, err := parseTime("end", r.URL.Query().Get("end"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	svc, err := h.readService(r, userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	events, err := svc.GetUserEvents(userID, start, end)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	if events == nil {
+		events = []*model.Event{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// CreateUserEvent creates event for the user from the path.
+func (h *EventHandler) CreateUserEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, err := decodeUserEvent(r, userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	conflicts, err := h.actingService(r, userID).CreateEventChecked(event, mode)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", eventLocation(userID, event.ID))
+	w.WriteHeader(http.StatusCreated)
+	writeEventResult(w, event, conflicts, mode)
+}
+
+// GetUserEvent returns single event of the user.
+func (h *EventHandler) GetUserEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, err := h.service.GetUserEvent(userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	writeEventWithETag(w, r, event)
+}
+
+// ReplaceUserEvent replaces event of the user with the full representation from the body.
+func (h *EventHandler) ReplaceUserEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	svc, err := h.eventWriter(r, userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, err := decodeUserEvent(r, userID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	event.ID = eventID
+
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	conflicts, err := svc.UpdateEventChecked(event, mode)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeEventResult(w, event, conflicts, mode)
+}
+
+// PatchUserEvent applies JSON Merge Patch to event of the user.
+func (h *EventHandler) PatchUserEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	svc, err := h.eventWriter(r, userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	patch, err := decodePatch(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	var fields struct {
+		UserID *int `json:"user_id"`
+	}
+	if json.Unmarshal(patch, &fields) == nil && fields.UserID != nil && *fields.UserID != userID {
+		handleErrorV2(w, errors.ValidationError{
+			Field:   "user_id",
+			Message: "event cannot be moved to another user",
+		})
+		return
+	}
+
+	mode, err := service.ParseConflictMode(r.URL.Query().Get("conflict"))
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	event, conflicts, err := svc.PatchEvent(eventID, patch, mode)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeEventResult(w, event, conflicts, mode)
+}
+
+// DeleteUserEvent deletes event of the user.
+func (h *EventHandler) DeleteUserEvent(w http.ResponseWriter, r *http.Request) {
+	userID, eventID, err := pathIDs(r)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	svc, err := h.eventWriter(r, userID, eventID)
+	if err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+
+	if err := svc.DeleteEvent(eventID); err != nil {
+		handleErrorV2(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeUserEvent decodes event from the body and binds it to the user from the path.
+func decodeUserEvent(r *http.Request, userID int) (*model.Event, error) {
+	event, err := decodeEvent(r)
+	if err != nil {
+		return nil, err
+	}
+	if event.UserID != 0 && event.UserID != userID {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user_id does not match the path",
+		}
+	}
+	event.UserID = userID
+	return event, nil
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"l2.18/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestV2_EventLifecycle(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup", "duration": 15}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "/v2/users/7/events/1", rec.Header().Get("Location"))
+
+	var created model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 7, created.UserID)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/8/events/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "event of another user")
+
+	rec = serve(router, http.MethodPut, "/v2/users/7/events/1",
+		`{"date": "2024-01-15T11:00:00Z", "text": "Standup moved"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodPatch, "/v2/users/7/events/1", `{"duration": 30}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var patched model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &patched))
+	assert.Equal(t, "Standup moved", patched.Text)
+	assert.Equal(t, 30, patched.Duration)
+
+	rec = serve(router, http.MethodPatch, "/v2/users/7/events/1", `{"user_id": 8}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events?start=2024-01-15&end=2024-01-16", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed []model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Len(t, listed, 1)
+
+	rec = serve(router, http.MethodDelete, "/v2/users/8/events/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(router, http.MethodDelete, "/v2/users/7/events/1", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestV2_CreateRejectsForeignUserID(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"user_id": 8, "date": "2024-01-15T10:00:00Z", "text": "Standup"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetEvent_ETag(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodGet, "/event/1?user_id=7", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = serve(router, http.MethodGet, "/event/1?user_id=8", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/event/1", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/users/7/events/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPatch, "/v2/users/7/events/1", strings.NewReader(`{"text": "Moved"}`))
+	req.Header.Set("If-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "precondition failed: update_event - event was modified\n", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodPatch, "/v2/users/7/events/1", strings.NewReader(`{"text": "Moved"}`))
+	req.Header.Set("If-Match", "W/"+etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Contains(t, rec.Body.String(), "event was modified")
+
+	req = httptest.NewRequest(http.MethodPatch, "/v2/users/7/events/1", strings.NewReader(`{"text": "Moved"}`))
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodGet, "/event/1?user_id=7", "")
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestV2_Trash(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodDelete, "/v2/users/7/events/1", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/trash", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trashed []model.TrashedEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trashed))
+	require.Len(t, trashed, 1)
+	assert.Equal(t, "Standup", trashed[0].Text)
+	assert.False(t, trashed[0].DeletedAt.IsZero())
+
+	rec = serve(router, http.MethodPost, "/v2/users/8/trash/1/restore", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/trash/1/restore", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	serve(router, http.MethodDelete, "/v2/users/7/events/1", "")
+	rec = serve(router, http.MethodDelete, "/v2/users/7/trash/1", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(router, http.MethodPost, "/v2/users/7/trash/1/restore", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestV2_Revisions(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := serve(router, http.MethodPost, "/v2/users/7/events",
+		`{"date": "2024-01-15T10:00:00Z", "text": "Standup"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = serve(router, http.MethodPatch, "/v2/users/7/events/1", `{"text": "Retro"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1/revisions", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var revisions []model.Revision
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &revisions))
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 7, revisions[1].Actor)
+	assert.Equal(t, "text", revisions[1].Changes[0].Field)
+
+	rec = serve(router, http.MethodGet, "/v2/users/8/events/1/revisions", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1/revisions/9", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(router, http.MethodGet, "/v2/users/7/events/1/revisions/1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var revision model.Revision
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &revision))
+	assert.Equal(t, "Standup", revision.Event.Text)
+
+	rec = serve(router, http.MethodPost, "/v2/users/7/events/1/revisions/1/rollback", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var event model.Event
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+	assert.Equal(t, "Standup", event.Text)
+}
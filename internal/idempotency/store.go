@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"l2.18/internal/clock"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrMismatch is returned when a key is reused for a different request.
+var ErrMismatch = errors.New("idempotency key was used for a different request")
+
+// Response is stored response replayed for retried requests.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type entry struct {
+	fingerprint string
+	done        chan struct{}
+	response    *Response
+	expires     time.Time
+}
+
+// Store keeps first response per key for ttl.
+type Store struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	ttl     time.Duration
+	entries map[string]*entry
+}
+
+// NewStore creates new Store.
+func NewStore(clk clock.Clock, ttl time.Duration) *Store {
+	return &Store{
+		clock:   clk,
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Begin starts request with key and request fingerprint.
+// For a known key it returns stored response, waiting while the first request is still in flight.
+// For a new key it returns finish, which must be called with the response to keep,
+// or with nil to forget the key so that the request can be retried.
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (*Response, func(*Response), error) {
+	for {
+		s.mu.Lock()
+		e, exists := s.entries[key]
+		if exists && e.response != nil && !s.clock.Now().Before(e.expires) {
+			delete(s.entries, key)
+			exists = false
+		}
+		if !exists {
+			e = &entry{fingerprint: fingerprint, done: make(chan struct{})}
+			s.entries[key] = e
+			s.mu.Unlock()
+			return nil, s.finish(key, e), nil
+		}
+		if e.fingerprint != fingerprint {
+			s.mu.Unlock()
+			return nil, nil, ErrMismatch
+		}
+		if e.response != nil {
+			response := e.response
+			s.mu.Unlock()
+			return response, nil, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// finish returns function completing in-flight entry.
+func (s *Store) finish(key string, e *entry) func(*Response) {
+	var once sync.Once
+	return func(response *Response) {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if response == nil {
+				delete(s.entries, key)
+			} else {
+				e.response = response
+				e.expires = s.clock.Now().Add(s.ttl)
+			}
+			close(e.done)
+		})
+	}
+}
+
+// Prune removes expired responses and returns how many were removed.
+func (s *Store) Prune() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	removed := 0
+	for key, e := range s.entries {
+		if e.response != nil && !now.Before(e.expires) {
+			delete(s.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Run prunes expired responses every interval until ctx is done.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Prune()
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"context"
+	"l2.18/internal/clock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_ReplaysFirstResponse(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	store := NewStore(clk, time.Hour)
+	ctx := context.Background()
+
+	response, finish, err := store.Begin(ctx, "1/key", "a")
+	require.NoError(t, err)
+	assert.Nil(t, response)
+	finish(&Response{Status: 201, Body: []byte("created")})
+
+	response, finish, err = store.Begin(ctx, "1/key", "a")
+	require.NoError(t, err)
+	assert.Nil(t, finish)
+	assert.Equal(t, []byte("created"), response.Body)
+
+	_, _, err = store.Begin(ctx, "1/key", "b")
+	assert.Equal(t, ErrMismatch, err)
+
+	_, finish, err = store.Begin(ctx, "2/key", "b")
+	require.NoError(t, err, "keys are scoped")
+	finish(&Response{Status: 201})
+
+	clk.Advance(time.Hour)
+	assert.Equal(t, 2, store.Prune())
+	response, finish, err = store.Begin(ctx, "1/key", "b")
+	require.NoError(t, err, "expired key can be reused")
+	assert.Nil(t, response)
+	finish(nil)
+}
+
+func TestStore_ForgetsFailedRequest(t *testing.T) {
+	store := NewStore(clock.NewFake(time.Now()), time.Hour)
+	ctx := context.Background()
+
+	_, finish, err := store.Begin(ctx, "key", "a")
+	require.NoError(t, err)
+	finish(nil)
+
+	response, finish, err := store.Begin(ctx, "key", "a")
+	require.NoError(t, err)
+	assert.Nil(t, response)
+	assert.NotNil(t, finish)
+}
+
+func TestStore_WaitsForInFlightRequest(t *testing.T) {
+	store := NewStore(clock.NewFake(time.Now()), time.Hour)
+	ctx := context.Background()
+
+	_, finish, err := store.Begin(ctx, "key", "a")
+	require.NoError(t, err)
+
+	replayed := make(chan *Response)
+	go func() {
+		response, _, _ := store.Begin(ctx, "key", "a")
+		replayed <- response
+	}()
+
+	select {
+	case <-replayed:
+		t.Fatal("duplicate must wait for the first request")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	finish(&Response{Status: 201, Body: []byte("created")})
+	assert.Equal(t, []byte("created"), (<-replayed).Body)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, finish, err = store.Begin(ctx, "other", "a")
+	require.NoError(t, err)
+	_, _, err = store.Begin(timeout, "other", "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	finish(nil)
+}
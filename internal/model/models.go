@@ -2,10 +2,320 @@ package model
 
 import "time"
 
+// MaxReminderMinutes is the largest allowed reminder offset (7 days).
+const MaxReminderMinutes = 7 * 24 * 60
+
+// MaxDurationMinutes is the longest allowed event (31 days).
+const MaxDurationMinutes = 31 * 24 * 60
+
 // Event struct holds events.
 type Event struct {
-	ID     int       `json:"id,omitempty"`
-	UserID int       `json:"user_id,omitempty"`
-	Date   time.Time `json:"date"`
-	Text   string    `json:"text"`
+	ID         int       `json:"id,omitempty"`
+	UserID     int       `json:"user_id,omitempty"`
+	CalendarID int       `json:"calendar_id,omitempty"`
+	Date       time.Time `json:"date"`
+	Text       string    `json:"text"`
+	Duration   int       `json:"duration,omitempty"`
+	Reminders  []int     `json:"reminders,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Category   string    `json:"category,omitempty"`
+	Color      string    `json:"color,omitempty"`
+}
+
+// End returns time when event finishes, Duration is in minutes.
+func (e *Event) End() time.Time {
+	return e.Date.Add(time.Duration(e.Duration) * time.Minute)
+}
+
+// Overlaps checks if event takes place within [start, end).
+func (e *Event) Overlaps(start, end time.Time) bool {
+	if e.Duration == 0 {
+		return !e.Date.Before(start) && e.Date.Before(end)
+	}
+	return e.Date.Before(end) && e.End().After(start)
+}
+
+// HasTag checks if event carries tag.
+func (e *Event) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderOffsets returns reminder offsets as durations before the event.
+func (e *Event) ReminderOffsets() []time.Duration {
+	offsets := make([]time.Duration, 0, len(e.Reminders))
+	for _, minutes := range e.Reminders {
+		offsets = append(offsets, time.Duration(minutes)*time.Minute)
+	}
+	return offsets
+}
+
+// DefaultCalendarName is name of calendar created for user's events that have no calendar.
+const DefaultCalendarName = "Default"
+
+// MaxCalendarNameLength limits length of calendar name.
+const MaxCalendarNameLength = 64
+
+// Calendar is user's named collection of events with its own color and time zone.
+type Calendar struct {
+	ID       int    `json:"id"`
+	OwnerID  int    `json:"owner_id"`
+	Name     string `json:"name"`
+	Color    string `json:"color,omitempty"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Default  bool   `json:"default"`
+	Role     Role   `json:"role,omitempty"`
+}
+
+// Role is user's level of access to a calendar.
+type Role string
+
+// Calendar roles, owner is the only one that cannot be granted.
+const (
+	RoleOwner    Role = "owner"
+	RoleEditor   Role = "editor"
+	RoleViewer   Role = "viewer"
+	RoleFreeBusy Role = "freebusy"
+)
+
+// Permission is kind of calendar access an operation needs.
+type Permission string
+
+// Calendar permissions, each role has some of them.
+const (
+	PermissionFreeBusy Permission = "freebusy"
+	PermissionRead     Permission = "read"
+	PermissionWrite    Permission = "write"
+	PermissionManage   Permission = "manage"
+)
+
+// rolePermissions lists permissions of every role.
+var rolePermissions = map[Role][]Permission{
+	RoleOwner:    {PermissionFreeBusy, PermissionRead, PermissionWrite, PermissionManage},
+	RoleEditor:   {PermissionFreeBusy, PermissionRead, PermissionWrite},
+	RoleViewer:   {PermissionFreeBusy, PermissionRead},
+	RoleFreeBusy: {PermissionFreeBusy},
+}
+
+// Allows checks if role has permission, unknown and empty roles have none.
+func (r Role) Allows(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Grantable checks if role can be given to other users.
+func (r Role) Grantable() bool {
+	return r == RoleEditor || r == RoleViewer || r == RoleFreeBusy
+}
+
+// Grant gives user a role in somebody else's calendar.
+type Grant struct {
+	CalendarID int  `json:"calendar_id"`
+	UserID     int  `json:"user_id"`
+	Role       Role `json:"role"`
+}
+
+// Feed is secret link publishing calendar read-only to anyone who knows its token.
+type Feed struct {
+	ID         int        `json:"id"`
+	CalendarID int        `json:"calendar_id"`
+	Token      string     `json:"token"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Hits       int        `json:"hits"`
+	LastHitAt  *time.Time `json:"last_hit_at,omitempty"`
+}
+
+// Expired checks if feed stopped working by now.
+func (f *Feed) Expired(now time.Time) bool {
+	return f.ExpiresAt != nil && !now.Before(*f.ExpiresAt)
+}
+
+// MaxEventTags limits number of tags on one event.
+const MaxEventTags = 20
+
+// MaxTagLength limits length of a tag and of a category name.
+const MaxTagLength = 32
+
+// Category is user's named kind of events with a display color.
+type Category struct {
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color,omitempty"`
+}
+
+// TagFilter selects events carrying every included tag and none of the excluded ones.
+type TagFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Empty checks if filter lets every event through.
+func (f TagFilter) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Matches checks if event passes the filter.
+func (f TagFilter) Matches(e *Event) bool {
+	for _, tag := range f.Include {
+		if !e.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range f.Exclude {
+		if e.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// User struct holds user's notification settings.
+type User struct {
+	ID          int    `json:"id"`
+	Email       string `json:"email,omitempty"`
+	EmailOptOut bool   `json:"email_opt_out"`
+	TimeZone    string `json:"time_zone,omitempty"`
+}
+
+// Interval holds a time span.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// FreeBusy holds busy intervals of one user or calendar without any event details.
+type FreeBusy struct {
+	UserID     int        `json:"user_id"`
+	CalendarID int        `json:"calendar_id,omitempty"`
+	Busy       []Interval `json:"busy"`
+}
+
+// SlotRequest holds parameters of a meeting slot search.
+type SlotRequest struct {
+	Attendees []int     `json:"attendees"`
+	Optional  []int     `json:"optional,omitempty"`
+	Duration  int       `json:"duration"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	WorkStart string    `json:"work_start,omitempty"`
+	WorkEnd   string    `json:"work_end,omitempty"`
+	MinGap    int       `json:"min_gap,omitempty"`
+	Step      int       `json:"step,omitempty"`
+	Count     int       `json:"count,omitempty"`
+	TimeZone  string    `json:"time_zone,omitempty"`
+}
+
+// Slot holds suggested meeting time and optional attendees who are busy then.
+type Slot struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	BusyOptional []int     `json:"busy_optional,omitempty"`
+}
+
+// Bucket is a granularity of event counts.
+type Bucket string
+
+// Supported buckets, weeks start on Monday.
+const (
+	BucketDay  Bucket = "day"
+	BucketWeek Bucket = "week"
+)
+
+// BucketStart returns midnight of the bucket containing t, in t's time zone.
+func (b Bucket) BucketStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if b == BucketWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns start of the bucket following the one starting at start.
+func (b Bucket) Next(start time.Time) time.Time {
+	if b == BucketWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// BucketCount holds number of events in a bucket.
+type BucketCount struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// TrashedEvent is deleted event kept in trash until it is restored or purged.
+type TrashedEvent struct {
+	Event
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// MaxBatchOperations limits number of operations in one batch.
+const MaxBatchOperations = 500
+
+// Batch operation kinds.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is one create, update or delete in a batch.
+type BatchOperation struct {
+	Op    string `json:"op"`
+	ID    int    `json:"id,omitempty"`
+	Event *Event `json:"event,omitempty"`
+}
+
+// Actor describes who makes a change and from where.
+type Actor struct {
+	UserID    int    `json:"user_id,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Actions changing events, purge and archive are only audited as purged and archived events have no revisions.
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+	RevisionRollback = "rollback"
+	RevisionPurge    = "purge"
+	RevisionArchive  = "archive"
+)
+
+// Revision is snapshot of event after a change, with fields changed since previous revision.
+// Delete revision holds event as it was when deleted.
+type Revision struct {
+	EventID int           `json:"event_id"`
+	Number  int           `json:"number"`
+	Action  string        `json:"action"`
+	Actor   int           `json:"actor"`
+	At      time.Time     `json:"at"`
+	Event   Event         `json:"event"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is change of single event field.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// SearchResult is event found by full-text search with its relevance.
+type SearchResult struct {
+	Event
+	Score float64 `json:"score"`
 }
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes notifications to a logger.
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier creates new LogNotifier, nil logger means standard one.
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+// Notify logs notification.
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	n.logger.Printf("[%s] user %d: event %d %q at %s",
+		notification.Kind, notification.UserID, notification.Event.ID,
+		notification.Event.Text, notification.Event.Date.Format("2006-01-02 15:04"))
+	return nil
+}
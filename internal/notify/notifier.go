@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"l2.18/internal/model"
+	"time"
+)
+
+// Kind describes why notification is sent.
+type Kind string
+
+// Kinds of notifications.
+const (
+	KindReminder      Kind = "reminder"
+	KindEventCreated  Kind = "event_created"
+	KindEventUpdated  Kind = "event_updated"
+	KindEventDeleted  Kind = "event_deleted"
+	KindEventRestored Kind = "event_restored"
+)
+
+// Notification holds data about single message for a user.
+type Notification struct {
+	Kind   Kind          `json:"kind"`
+	UserID int           `json:"user_id"`
+	Event  model.Event   `json:"event"`
+	Offset time.Duration `json:"offset,omitempty"`
+	FireAt time.Time     `json:"fire_at,omitempty"`
+}
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Multi sends notification through every notifier and returns the first error.
+type Multi []Notifier
+
+// Notify delivers notification through all notifiers.
+func (m Multi) Notify(ctx context.Context, n Notification) error {
+	var firstErr error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"l2.18/internal/clock"
+	"log"
+	"sync"
+	"time"
+)
+
+// RetryQueue sends notifications in background and retries failed ones with exponential backoff.
+type RetryQueue struct {
+	mu          sync.Mutex
+	next        Notifier
+	clock       clock.Clock
+	items       []*queueItem
+	capacity    int
+	maxAttempts int
+	backoff     time.Duration
+	onDrop      func(n Notification, err error)
+	wake        chan struct{}
+}
+
+type queueItem struct {
+	notification Notification
+	attempts     int
+	due          time.Time
+}
+
+// NewRetryQueue creates new RetryQueue in front of provided notifier.
+func NewRetryQueue(next Notifier, clk clock.Clock, capacity, maxAttempts int, backoff time.Duration) *RetryQueue {
+	return &RetryQueue{
+		next:        next,
+		clock:       clk,
+		capacity:    capacity,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Notify enqueues notification, it is delivered by Run.
+func (q *RetryQueue) Notify(_ context.Context, n Notification) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) >= q.capacity {
+		return errors.New("notification queue is full")
+	}
+	q.items = append(q.items, &queueItem{notification: n, due: q.clock.Now()})
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// OnDrop sets fn called with every notification dropped after its last failed attempt.
+func (q *RetryQueue) OnDrop(fn func(n Notification, err error)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onDrop = fn
+}
+
+// Len returns number of notifications waiting for delivery.
+func (q *RetryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Run delivers queued notifications until context is cancelled.
+func (q *RetryQueue) Run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// Flush tries to deliver every notification that is due and returns how many were delivered.
+func (q *RetryQueue) Flush(ctx context.Context) int {
+	now := q.clock.Now()
+
+	q.mu.Lock()
+	var due []*queueItem
+	pending := q.items[:0]
+	for _, item := range q.items {
+		if item.due.After(now) {
+			pending = append(pending, item)
+		} else {
+			due = append(due, item)
+		}
+	}
+	q.items = pending
+	onDrop := q.onDrop
+	q.mu.Unlock()
+
+	delivered := 0
+	var retry []*queueItem
+	for _, item := range due {
+		err := q.next.Notify(ctx, item.notification)
+		if err == nil {
+			delivered++
+			continue
+		}
+
+		item.attempts++
+		if item.attempts >= q.maxAttempts {
+			log.Printf("Dropping %s notification for user %d after %d attempts: %v",
+				item.notification.Kind, item.notification.UserID, item.attempts, err)
+			if onDrop != nil {
+				onDrop(item.notification, err)
+			}
+			continue
+		}
+		item.due = now.Add(q.backoff << (item.attempts - 1))
+		retry = append(retry, item)
+	}
+
+	if len(retry) > 0 {
+		q.mu.Lock()
+		q.items = append(q.items, retry...)
+		q.mu.Unlock()
+	}
+	return delivered
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"l2.18/internal/clock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingNotifier struct {
+	calls int
+}
+
+func (n *failingNotifier) Notify(context.Context, Notification) error {
+	n.calls++
+	return errors.New("unavailable")
+}
+
+func TestRetryQueue_DropsAfterMaxAttempts(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	next := &failingNotifier{}
+	queue := NewRetryQueue(next, clk, 10, 3, time.Minute)
+
+	require.NoError(t, queue.Notify(context.Background(), Notification{Kind: KindReminder, UserID: 1}))
+
+	for _, wait := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+		clk.Advance(wait)
+		queue.Flush(context.Background())
+	}
+
+	assert.Equal(t, 3, next.calls)
+	assert.Zero(t, queue.Len())
+}
+
+func TestRetryQueue_RejectsWhenFull(t *testing.T) {
+	queue := NewRetryQueue(&failingNotifier{}, clock.Real{}, 1, 3, time.Minute)
+
+	require.NoError(t, queue.Notify(context.Background(), Notification{Kind: KindReminder}))
+	assert.Error(t, queue.Notify(context.Background(), Notification{Kind: KindReminder}))
+}
+
+func TestRetryQueue_ReportsDropped(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	queue := NewRetryQueue(&failingNotifier{}, clk, 10, 1, time.Minute)
+	var dropped []Notification
+	queue.OnDrop(func(n Notification, err error) {
+		assert.EqualError(t, err, "unavailable")
+		dropped = append(dropped, n)
+	})
+
+	require.NoError(t, queue.Notify(context.Background(), Notification{Kind: KindReminder, UserID: 1}))
+	queue.Flush(context.Background())
+
+	require.Len(t, dropped, 1)
+	assert.Equal(t, 1, dropped[0].UserID)
+}
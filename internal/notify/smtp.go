@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"l2.18/internal/model"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Recipients looks up users' email settings.
+type Recipients interface {
+	GetUser(id int) (*model.User, error)
+}
+
+// SMTPConfig holds SMTP server connection data.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPNotifier sends notifications by email.
+type SMTPNotifier struct {
+	cfg        SMTPConfig
+	recipients Recipients
+	templates  *Templates
+}
+
+// NewSMTPNotifier creates new SMTPNotifier.
+func NewSMTPNotifier(cfg SMTPConfig, recipients Recipients, templates *Templates) *SMTPNotifier {
+	return &SMTPNotifier{
+		cfg:        cfg,
+		recipients: recipients,
+		templates:  templates,
+	}
+}
+
+// Notify emails notification to the user, unknown users, users without email or opted out are skipped.
+func (n *SMTPNotifier) Notify(ctx context.Context, notification Notification) error {
+	user, err := n.recipients.GetUser(notification.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return fmt.Errorf("get recipient %d: %w", notification.UserID, err)
+	}
+	if user.Email == "" || user.EmailOptOut {
+		return nil
+	}
+	to, err := mail.ParseAddress(user.Email)
+	if err != nil {
+		return fmt.Errorf("email of user %d: %w", user.ID, err)
+	}
+
+	subject, text, html, err := n.templates.Render(notification)
+	if err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(n.cfg.From, to.String(), subject, text, html)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, to.Address, msg)
+}
+
+// send delivers message through configured SMTP server.
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	dialer := net.Dialer{Timeout: n.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if n.cfg.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(n.cfg.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		auth := smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage builds multipart/alternative email with text and HTML parts, quoted-printable so any UTF-8
+// text passes 7-bit servers.
+func buildMessage(from, to, subject, text, html string) ([]byte, error) {
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := hex.EncodeToString(boundaryBytes)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	if err := writePart(&buf, boundary, "text/plain", text); err != nil {
+		return nil, err
+	}
+	if err := writePart(&buf, boundary, "text/html", html); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+// writePart writes part of multipart message with body in quoted-printable encoding.
+func writePart(buf *bytes.Buffer, boundary, contentType, body string) error {
+	fmt.Fprintf(buf, "--%s\r\n", boundary)
+	fmt.Fprintf(buf, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	buf.WriteString("\r\n")
+	return nil
+}
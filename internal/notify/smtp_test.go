@@ -0,0 +1,277 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal in-process SMTP server that records received messages.
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []fakeMessage
+	authed   bool
+}
+
+type fakeMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) received() []fakeMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeMessage(nil), s.messages...)
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost fake SMTP")
+	var msg fakeMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN"):
+			s.mu.Lock()
+			s.authed = true
+			s.mu.Unlock()
+			reply("235 authenticated")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = fakeMessage{from: strings.Trim(line[len("MAIL FROM:"):], "<>")}
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			msg.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func newTestSMTPNotifier(t *testing.T, port int, users *repository.MemoryUserRepository) *SMTPNotifier {
+	templates, err := NewTemplates("", "")
+	require.NoError(t, err)
+	return NewSMTPNotifier(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Username: "calendar",
+		Password: "secret",
+		From:     "calendar@example.com",
+		Timeout:  5 * time.Second,
+	}, users, templates)
+}
+
+func TestSMTPNotifier_SendsTextAndHTML(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	users := repository.NewMemoryUserRepository()
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, Email: "alice@example.com"}))
+
+	notifier := newTestSMTPNotifier(t, server.port(), users)
+	err := notifier.Notify(context.Background(), Notification{
+		Kind:   KindReminder,
+		UserID: 1,
+		Event:  model.Event{ID: 7, UserID: 1, Text: "Dentist", Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		Offset: 15 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	messages := server.received()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "calendar@example.com", messages[0].from)
+	assert.Equal(t, []string{"alice@example.com"}, messages[0].to)
+	assert.Contains(t, messages[0].data, "Subject: Reminder: Dentist")
+	assert.Contains(t, messages[0].data, "Content-Type: text/plain")
+	assert.Contains(t, messages[0].data, "Content-Type: text/html")
+	assert.Contains(t, messages[0].data, "Starts in: 15m")
+	assert.True(t, server.authed)
+}
+
+func TestBuildMessage_QuotedPrintable(t *testing.T) {
+	text := "Напоминание: стоматолог в 10:00"
+	html := "<p>Напоминание: <b>стоматолог</b></p>"
+	data, err := buildMessage("calendar@example.com", "alice@example.com", "Напоминание", text, html)
+	require.NoError(t, err)
+
+	for _, b := range data {
+		require.Less(t, b, byte(0x80), "message is 7-bit")
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []string{text, html} {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		assert.Equal(t, want, strings.TrimRight(string(body), "\r\n"))
+	}
+}
+
+func TestSMTPNotifier_SkipsOptedOutUsers(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	users := repository.NewMemoryUserRepository()
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, Email: "bob@example.com", EmailOptOut: true}))
+
+	notifier := newTestSMTPNotifier(t, server.port(), users)
+	for _, userID := range []int{1, 2} {
+		err := notifier.Notify(context.Background(), Notification{
+			Kind:   KindEventCreated,
+			UserID: userID,
+			Event:  model.Event{ID: 1, UserID: userID, Text: "Standup", Date: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Empty(t, server.received())
+}
+
+func TestSMTPNotifier_SendsToBareAddress(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	users := repository.NewMemoryUserRepository()
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, Email: "Alice Smith <alice@example.com>"}))
+
+	notifier := newTestSMTPNotifier(t, server.port(), users)
+	require.NoError(t, notifier.Notify(context.Background(), Notification{
+		Kind:   KindEventCreated,
+		UserID: 1,
+		Event:  model.Event{ID: 1, UserID: 1, Text: "Standup", Date: time.Now()},
+	}))
+
+	messages := server.received()
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"alice@example.com"}, messages[0].to)
+	assert.Contains(t, messages[0].data, `To: "Alice Smith" <alice@example.com>`)
+}
+
+// failingRecipients fails every lookup.
+type failingRecipients struct{}
+
+func (failingRecipients) GetUser(id int) (*model.User, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestSMTPNotifier_ReportsLookupFailures(t *testing.T) {
+	templates, err := NewTemplates("", "")
+	require.NoError(t, err)
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1"}, failingRecipients{}, templates)
+
+	err = notifier.Notify(context.Background(), Notification{
+		Kind:   KindEventCreated,
+		UserID: 1,
+		Event:  model.Event{ID: 1, UserID: 1, Text: "Standup", Date: time.Now()},
+	})
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestRetryQueue_RetriesWithBackoff(t *testing.T) {
+	users := repository.NewMemoryUserRepository()
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, Email: "alice@example.com"}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	clk := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	queue := NewRetryQueue(newTestSMTPNotifier(t, port, users), clk, 10, 3, time.Minute)
+
+	require.NoError(t, queue.Notify(context.Background(), Notification{
+		Kind:   KindEventUpdated,
+		UserID: 1,
+		Event:  model.Event{ID: 1, UserID: 1, Text: "Retro", Date: time.Now()},
+	}))
+
+	assert.Zero(t, queue.Flush(context.Background()))
+	assert.Equal(t, 1, queue.Len())
+
+	server := &fakeSMTPServer{}
+	server.listener, err = net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	require.NoError(t, err)
+	go server.serve()
+	defer server.listener.Close()
+
+	assert.Zero(t, queue.Flush(context.Background()), "retry must wait for backoff")
+
+	clk.Advance(time.Minute)
+	assert.Equal(t, 1, queue.Flush(context.Background()))
+	assert.Zero(t, queue.Len())
+	assert.Len(t, server.received(), 1)
+}
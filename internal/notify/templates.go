@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// mailData is what email templates are rendered with.
+type mailData struct {
+	Subject string
+	Text    string
+	Date    string
+	Offset  string
+}
+
+var subjects = map[Kind]string{
+	KindReminder:      "Reminder: ",
+	KindEventCreated:  "New event: ",
+	KindEventUpdated:  "Event changed: ",
+	KindEventDeleted:  "Event cancelled: ",
+	KindEventRestored: "Event restored: ",
+}
+
+const textBody = `{{.Subject}}
+
+Event: {{.Text}}
+When: {{.Date}}
+{{- if .Offset}}
+Starts in: {{.Offset}}
+{{- end}}
+`
+
+const htmlBody = `<html><body>
+<h3>{{.Subject}}</h3>
+<p><b>Event:</b> {{.Text}}</p>
+<p><b>When:</b> {{.Date}}</p>
+{{- if .Offset}}
+<p><b>Starts in:</b> {{.Offset}}</p>
+{{- end}}
+</body></html>
+`
+
+// Templates renders subject, plain text and HTML parts of an email.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewTemplates parses provided templates, empty strings mean default ones.
+func NewTemplates(text, html string) (*Templates, error) {
+	if text == "" {
+		text = textBody
+	}
+	if html == "" {
+		html = htmlBody
+	}
+
+	textTmpl, err := texttemplate.New("text").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	htmlTmpl, err := htmltemplate.New("html").Parse(html)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Templates{
+		text: textTmpl,
+		html: htmlTmpl,
+	}, nil
+}
+
+// Render renders notification into subject, text and HTML bodies.
+func (t *Templates) Render(n Notification) (subject, text, html string, err error) {
+	data := mailData{
+		Subject: subjects[n.Kind] + n.Event.Text,
+		Text:    n.Event.Text,
+		Date:    n.Event.Date.Format("2006-01-02 15:04 MST"),
+	}
+	if n.Offset > 0 {
+		data.Offset = strings.TrimSuffix(n.Offset.String(), "0s")
+	}
+
+	var textBuf, htmlBuf bytes.Buffer
+	if err := t.text.Execute(&textBuf, data); err != nil {
+		return "", "", "", err
+	}
+	if err := t.html.Execute(&htmlBuf, data); err != nil {
+		return "", "", "", err
+	}
+	return data.Subject, textBuf.String(), htmlBuf.String(), nil
+}
@@ -0,0 +1,124 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/notify"
+	"l2.18/internal/repository"
+	"log"
+	"time"
+)
+
+// Scheduler periodically looks for due reminders and sends them through notifier.
+type Scheduler struct {
+	repo     repository.Repository
+	notifier notify.Notifier
+	store    FiredStore
+	clock    clock.Clock
+	interval time.Duration
+}
+
+// NewScheduler creates new Scheduler.
+func NewScheduler(repo repository.Repository, notifier notify.Notifier, store FiredStore,
+	clk clock.Clock, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		store:    store,
+		clock:    clk,
+		interval: interval,
+	}
+}
+
+// Run checks reminders every interval until context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			log.Printf("Reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends every reminder that is due at current clock time and returns how many were sent.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	horizon := now.Add(time.Duration(model.MaxReminderMinutes) * time.Minute)
+
+	events, err := s.repo.GetEventsRange(now, horizon)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var firstErr error
+	for _, event := range events {
+		if !now.Before(event.Date) {
+			continue
+		}
+		for _, offset := range event.ReminderOffsets() {
+			fireAt := event.Date.Add(-offset)
+			if fireAt.After(now) {
+				continue
+			}
+
+			key := reminderKey(event, offset)
+			if s.store.WasFired(key) {
+				continue
+			}
+
+			// Marked before sending, so a notification dropped later by a queue can unmark it in any order.
+			if err := s.store.MarkFired(key, event.Date); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("mark reminder fired: %w", err)
+			}
+			err := s.notifier.Notify(ctx, notify.Notification{
+				Kind:   notify.KindReminder,
+				UserID: event.UserID,
+				Event:  *event,
+				Offset: offset,
+				FireAt: fireAt,
+			})
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("notify event %d: %w", event.ID, err)
+				}
+				if err := s.store.Unmark(key); err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("unmark reminder: %w", err)
+				}
+				continue
+			}
+			sent++
+		}
+	}
+
+	if err := s.store.Prune(now); err != nil && firstErr == nil {
+		firstErr = fmt.Errorf("prune fired reminders: %w", err)
+	}
+	return sent, firstErr
+}
+
+// Dropped makes reminder whose notification was dropped undelivered due again, so the next run resends it
+// until the event starts. Other notifications are ignored.
+func (s *Scheduler) Dropped(n notify.Notification, _ error) {
+	if n.Kind != notify.KindReminder {
+		return
+	}
+	if err := s.store.Unmark(reminderKey(&n.Event, n.Offset)); err != nil {
+		log.Printf("Reminder scheduler: unmark dropped reminder of event %d: %v", n.Event.ID, err)
+	}
+}
+
+// reminderKey identifies single reminder, moving the event re-arms its reminders.
+func reminderKey(event *model.Event, offset time.Duration) string {
+	return fmt.Sprintf("%d/%d/%d", event.ID, event.Date.Unix(), int64(offset/time.Minute))
+}
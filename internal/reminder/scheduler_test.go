@@ -0,0 +1,160 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/notify"
+	"l2.18/internal/repository"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notify.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func newTestScheduler(t *testing.T, repo repository.Repository, notifier notify.Notifier,
+	clk clock.Clock, path string) *Scheduler {
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	return NewScheduler(repo, notifier, store, clk, time.Minute)
+}
+
+func TestScheduler_FiresDueRemindersOnce(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.Add(-2 * time.Hour))
+	notifier := &recordingNotifier{}
+	scheduler := newTestScheduler(t, repo, notifier, clk, filepath.Join(t.TempDir(), "fired.json"))
+
+	event := &model.Event{UserID: 1, Date: start, Text: "Meeting", Reminders: []int{15, 60}}
+	require.NoError(t, repo.CreateEvent(event))
+
+	sent, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	clk.Set(start.Add(-time.Hour))
+	sent, err = scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	sent, err = scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	clk.Advance(50 * time.Minute)
+	sent, err = scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, time.Hour, notifier.sent[0].Offset)
+	assert.Equal(t, 15*time.Minute, notifier.sent[1].Offset)
+	assert.Equal(t, 1, notifier.sent[1].UserID)
+}
+
+func TestScheduler_DoesNotRefireAfterRestart(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.Add(-10 * time.Minute))
+	path := filepath.Join(t.TempDir(), "fired.json")
+
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 1, Date: start, Text: "Call", Reminders: []int{15}}))
+
+	first := &recordingNotifier{}
+	sent, err := newTestScheduler(t, repo, first, clk, path).RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	second := &recordingNotifier{}
+	sent, err = newTestScheduler(t, repo, second, clk, path).RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, second.sent)
+}
+
+func TestScheduler_RetriesFailedNotification(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.Add(-5 * time.Minute))
+	notifier := &recordingNotifier{err: errors.New("unavailable")}
+	scheduler := newTestScheduler(t, repo, notifier, clk, filepath.Join(t.TempDir(), "fired.json"))
+
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 1, Date: start, Text: "Call", Reminders: []int{15}}))
+
+	_, err := scheduler.RunOnce(context.Background())
+	assert.Error(t, err)
+
+	notifier.err = nil
+	sent, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func TestScheduler_RearmsMovedEvent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.Add(-10 * time.Minute))
+	notifier := &recordingNotifier{}
+	scheduler := newTestScheduler(t, repo, notifier, clk, filepath.Join(t.TempDir(), "fired.json"))
+
+	event := &model.Event{UserID: 1, Date: start, Text: "Call", Reminders: []int{15}}
+	require.NoError(t, repo.CreateEvent(event))
+
+	_, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	moved := *event
+	moved.Date = start.Add(5 * time.Minute)
+	require.NoError(t, repo.UpdateEvent(event.ID, &moved))
+
+	sent, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, notifier.sent, 2)
+}
+
+func TestScheduler_ResendsDroppedReminder(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.Add(-10 * time.Minute))
+	next := &recordingNotifier{err: errors.New("unavailable")}
+	queue := notify.NewRetryQueue(next, clk, 10, 1, time.Minute)
+	scheduler := newTestScheduler(t, repo, queue, clk, filepath.Join(t.TempDir(), "fired.json"))
+	queue.OnDrop(scheduler.Dropped)
+
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 1, Date: start, Text: "Call", Reminders: []int{15}}))
+
+	sent, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "reminder is queued")
+	assert.Zero(t, queue.Flush(context.Background()), "queue drops it")
+
+	next.err = nil
+	sent, err = scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "dropped reminder is due again")
+	assert.Equal(t, 1, queue.Flush(context.Background()))
+	require.Len(t, next.sent, 1)
+	assert.Equal(t, 15*time.Minute, next.sent[0].Offset)
+}
@@ -0,0 +1,116 @@
+package reminder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FiredStore remembers which reminders were already sent.
+type FiredStore interface {
+	WasFired(key string) bool
+	MarkFired(key string, eventDate time.Time) error
+	Unmark(key string) error
+	Prune(before time.Time) error
+}
+
+// FileStore keeps fired reminders in a JSON file, so they are not sent again after restart.
+type FileStore struct {
+	mu    sync.Mutex
+	path  string
+	fired map[string]time.Time
+}
+
+// NewFileStore creates FileStore and loads already fired reminders from the file.
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{
+		path:  path,
+		fired: make(map[string]time.Time),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return store, nil
+	}
+	if err := json.Unmarshal(data, &store.fired); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// WasFired checks if reminder with provided key was sent.
+func (s *FileStore) WasFired(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.fired[key]
+	return exists
+}
+
+// MarkFired saves reminder as sent.
+func (s *FileStore) MarkFired(key string, eventDate time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fired[key] = eventDate
+	return s.save()
+}
+
+// Unmark forgets that reminder was sent, so it is sent again.
+func (s *FileStore) Unmark(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.fired[key]; !exists {
+		return nil
+	}
+	delete(s.fired, key)
+	return s.save()
+}
+
+// Prune forgets reminders of events that started before provided time.
+func (s *FileStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := false
+	for key, eventDate := range s.fired {
+		if eventDate.Before(before) {
+			delete(s.fired, key)
+			pruned = true
+		}
+	}
+	if !pruned {
+		return nil
+	}
+	return s.save()
+}
+
+// save writes fired reminders to a temporary file and renames it over the old one.
+func (s *FileStore) save() error {
+	dir := filepath.Dir(s.path)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(s.fired)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sort"
+	"sync"
+)
+
+// MemoryCalendarRepository struct holds users' calendars.
+type MemoryCalendarRepository struct {
+	mu        sync.RWMutex
+	calendars map[int]*model.Calendar
+	nextID    int
+}
+
+// NewMemoryCalendarRepository creates new MemoryCalendarRepository.
+func NewMemoryCalendarRepository() *MemoryCalendarRepository {
+	return &MemoryCalendarRepository{
+		calendars: make(map[int]*model.Calendar),
+		nextID:    1,
+	}
+}
+
+// CreateCalendar adds new calendar and assigns its id.
+func (r *MemoryCalendarRepository) CreateCalendar(calendar *model.Calendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.create(calendar)
+	return nil
+}
+
+// GetCalendar gets calendar by id.
+func (r *MemoryCalendarRepository) GetCalendar(id int) (*model.Calendar, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	calendar, exists := r.calendars[id]
+	if !exists {
+		return nil, errors.New("calendar not found")
+	}
+	stored := *calendar
+	return &stored, nil
+}
+
+// GetCalendars gets calendars owned by user in creation order.
+func (r *MemoryCalendarRepository) GetCalendars(ownerID int) ([]*model.Calendar, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	calendars := []*model.Calendar{}
+	for _, calendar := range r.calendars {
+		if calendar.OwnerID == ownerID {
+			stored := *calendar
+			calendars = append(calendars, &stored)
+		}
+	}
+
+	sort.Slice(calendars, func(i, j int) bool {
+		return calendars[i].ID < calendars[j].ID
+	})
+	return calendars, nil
+}
+
+// UpdateCalendar replaces stored calendar.
+func (r *MemoryCalendarRepository) UpdateCalendar(calendar *model.Calendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.calendars[calendar.ID]; !exists {
+		return errors.New("calendar not found")
+	}
+	stored := *calendar
+	r.calendars[calendar.ID] = &stored
+	return nil
+}
+
+// DeleteCalendar deletes calendar by id.
+func (r *MemoryCalendarRepository) DeleteCalendar(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.calendars[id]; !exists {
+		return errors.New("calendar not found")
+	}
+	delete(r.calendars, id)
+	return nil
+}
+
+// DefaultCalendar gets user's default calendar, creating it on first use.
+func (r *MemoryCalendarRepository) DefaultCalendar(ownerID int) (*model.Calendar, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, calendar := range r.calendars {
+		if calendar.OwnerID == ownerID && calendar.Default {
+			stored := *calendar
+			return &stored, nil
+		}
+	}
+
+	calendar := &model.Calendar{OwnerID: ownerID, Name: model.DefaultCalendarName, Default: true}
+	r.create(calendar)
+	return calendar, nil
+}
+
+// create stores calendar under next id. Caller must hold write lock.
+func (r *MemoryCalendarRepository) create(calendar *model.Calendar) {
+	calendar.ID = r.nextID
+	stored := *calendar
+	r.calendars[calendar.ID] = &stored
+	r.nextID++
+}
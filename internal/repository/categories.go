@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sort"
+	"sync"
+)
+
+// MemoryCategoryRepository struct holds users' event categories.
+type MemoryCategoryRepository struct {
+	mu         sync.RWMutex
+	categories map[int]map[string]*model.Category
+}
+
+// NewMemoryCategoryRepository creates new MemoryCategoryRepository.
+func NewMemoryCategoryRepository() *MemoryCategoryRepository {
+	return &MemoryCategoryRepository{
+		categories: make(map[int]map[string]*model.Category),
+	}
+}
+
+// GetCategories gets user's categories ordered by name.
+func (r *MemoryCategoryRepository) GetCategories(userID int) ([]*model.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := []*model.Category{}
+	for _, category := range r.categories[userID] {
+		stored := *category
+		categories = append(categories, &stored)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+// GetCategory gets user's category by name.
+func (r *MemoryCategoryRepository) GetCategory(userID int, name string) (*model.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	category, exists := r.categories[userID][name]
+	if !exists {
+		return nil, errors.New("category not found")
+	}
+	stored := *category
+	return &stored, nil
+}
+
+// SaveCategory creates or replaces user's category.
+func (r *MemoryCategoryRepository) SaveCategory(category *model.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.categories[category.UserID] == nil {
+		r.categories[category.UserID] = make(map[string]*model.Category)
+	}
+	stored := *category
+	r.categories[category.UserID][category.Name] = &stored
+	return nil
+}
+
+// DeleteCategory deletes user's category by name.
+func (r *MemoryCategoryRepository) DeleteCategory(userID int, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.categories[userID][name]; !exists {
+		return errors.New("category not found")
+	}
+	delete(r.categories[userID], name)
+	return nil
+}
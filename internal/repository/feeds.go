@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryFeedRepository struct holds feed links indexed by id and by token.
+type MemoryFeedRepository struct {
+	mu     sync.RWMutex
+	feeds  map[int]*model.Feed
+	tokens map[string]int
+	nextID int
+}
+
+// NewMemoryFeedRepository creates new MemoryFeedRepository.
+func NewMemoryFeedRepository() *MemoryFeedRepository {
+	return &MemoryFeedRepository{
+		feeds:  make(map[int]*model.Feed),
+		tokens: make(map[string]int),
+		nextID: 1,
+	}
+}
+
+// CreateFeed adds new feed and assigns its id, tokens must be unique.
+func (r *MemoryFeedRepository) CreateFeed(feed *model.Feed) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[feed.Token]; exists {
+		return errors.New("feed token already exists")
+	}
+	feed.ID = r.nextID
+	r.nextID++
+	r.feeds[feed.ID] = copyFeed(feed)
+	r.tokens[feed.Token] = feed.ID
+	return nil
+}
+
+// GetFeed gets feed by id.
+func (r *MemoryFeedRepository) GetFeed(id int) (*model.Feed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	feed, exists := r.feeds[id]
+	if !exists {
+		return nil, errors.New("feed not found")
+	}
+	return copyFeed(feed), nil
+}
+
+// GetFeeds gets feeds of calendar ordered by id.
+func (r *MemoryFeedRepository) GetFeeds(calendarID int) ([]*model.Feed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	feeds := []*model.Feed{}
+	for _, feed := range r.feeds {
+		if feed.CalendarID == calendarID {
+			feeds = append(feeds, copyFeed(feed))
+		}
+	}
+
+	sort.Slice(feeds, func(i, j int) bool {
+		return feeds[i].ID < feeds[j].ID
+	})
+	return feeds, nil
+}
+
+// UpdateFeed replaces feed, a changed token stops the old one from working.
+func (r *MemoryFeedRepository) UpdateFeed(feed *model.Feed) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.feeds[feed.ID]
+	if !exists {
+		return errors.New("feed not found")
+	}
+	if id, exists := r.tokens[feed.Token]; exists && id != feed.ID {
+		return errors.New("feed token already exists")
+	}
+	delete(r.tokens, stored.Token)
+	r.feeds[feed.ID] = copyFeed(feed)
+	r.tokens[feed.Token] = feed.ID
+	return nil
+}
+
+// DeleteFeed deletes feed by id.
+func (r *MemoryFeedRepository) DeleteFeed(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feed, exists := r.feeds[id]
+	if !exists {
+		return errors.New("feed not found")
+	}
+	delete(r.tokens, feed.Token)
+	delete(r.feeds, id)
+	return nil
+}
+
+// DeleteCalendarFeeds deletes all feeds of calendar.
+func (r *MemoryFeedRepository) DeleteCalendarFeeds(calendarID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, feed := range r.feeds {
+		if feed.CalendarID == calendarID {
+			delete(r.tokens, feed.Token)
+			delete(r.feeds, id)
+		}
+	}
+	return nil
+}
+
+// FindFeed finds feed by token, expired feeds are not found.
+func (r *MemoryFeedRepository) FindFeed(token string, at time.Time) (*model.Feed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.tokens[token]
+	if !exists || r.feeds[id].Expired(at) {
+		return nil, errors.New("feed not found")
+	}
+	return copyFeed(r.feeds[id]), nil
+}
+
+// HitFeed counts access to feed by token at provided time.
+func (r *MemoryFeedRepository) HitFeed(token string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, exists := r.tokens[token]
+	if !exists {
+		return errors.New("feed not found")
+	}
+	feed := r.feeds[id]
+	feed.Hits++
+	feed.LastHitAt = &at
+	return nil
+}
+
+// copyFeed copies feed along with its optional times.
+func copyFeed(feed *model.Feed) *model.Feed {
+	copied := *feed
+	if feed.ExpiresAt != nil {
+		expiresAt := *feed.ExpiresAt
+		copied.ExpiresAt = &expiresAt
+	}
+	if feed.LastHitAt != nil {
+		lastHitAt := *feed.LastHitAt
+		copied.LastHitAt = &lastHitAt
+	}
+	return &copied
+}
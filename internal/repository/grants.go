@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sort"
+	"sync"
+)
+
+// MemoryGrantRepository struct holds calendar grants keyed by calendar and user.
+type MemoryGrantRepository struct {
+	mu     sync.RWMutex
+	grants map[int]map[int]model.Role
+}
+
+// NewMemoryGrantRepository creates new MemoryGrantRepository.
+func NewMemoryGrantRepository() *MemoryGrantRepository {
+	return &MemoryGrantRepository{
+		grants: make(map[int]map[int]model.Role),
+	}
+}
+
+// SaveGrant creates or replaces user's role in calendar.
+func (r *MemoryGrantRepository) SaveGrant(grant *model.Grant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.grants[grant.CalendarID] == nil {
+		r.grants[grant.CalendarID] = make(map[int]model.Role)
+	}
+	r.grants[grant.CalendarID][grant.UserID] = grant.Role
+	return nil
+}
+
+// GetGrant gets user's grant in calendar.
+func (r *MemoryGrantRepository) GetGrant(calendarID, userID int) (*model.Grant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, exists := r.grants[calendarID][userID]
+	if !exists {
+		return nil, errors.New("grant not found")
+	}
+	return &model.Grant{CalendarID: calendarID, UserID: userID, Role: role}, nil
+}
+
+// GetGrants gets grants of calendar ordered by user.
+func (r *MemoryGrantRepository) GetGrants(calendarID int) ([]*model.Grant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	grants := []*model.Grant{}
+	for userID, role := range r.grants[calendarID] {
+		grants = append(grants, &model.Grant{CalendarID: calendarID, UserID: userID, Role: role})
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].UserID < grants[j].UserID
+	})
+	return grants, nil
+}
+
+// GetUserGrants gets grants given to user ordered by calendar.
+func (r *MemoryGrantRepository) GetUserGrants(userID int) ([]*model.Grant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	grants := []*model.Grant{}
+	for calendarID, users := range r.grants {
+		if role, exists := users[userID]; exists {
+			grants = append(grants, &model.Grant{CalendarID: calendarID, UserID: userID, Role: role})
+		}
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].CalendarID < grants[j].CalendarID
+	})
+	return grants, nil
+}
+
+// DeleteGrant revokes user's role in calendar.
+func (r *MemoryGrantRepository) DeleteGrant(calendarID, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.grants[calendarID][userID]; !exists {
+		return errors.New("grant not found")
+	}
+	delete(r.grants[calendarID], userID)
+	return nil
+}
+
+// DeleteCalendarGrants revokes all grants of calendar.
+func (r *MemoryGrantRepository) DeleteCalendarGrants(calendarID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.grants, calendarID)
+	return nil
+}
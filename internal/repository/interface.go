@@ -8,9 +8,77 @@ import (
 // Repository interface that holds function for CRUD operations with events.
 type Repository interface {
 	CreateEvent(event *model.Event) error
+	SaveEvent(event *model.Event) error
+	GetEvent(id int) (*model.Event, error)
 	UpdateEvent(id int, updateEvent *model.Event) error
 	DeleteEvent(eventID int) error
 	GetEventDay(date time.Time) ([]*model.Event, error)
 	GetEventWeek(dateStart, dateEnd time.Time) ([]*model.Event, error)
 	GetEventMonth(dateStart, dateEnd time.Time) ([]*model.Event, error)
+	GetEventsRange(dateStart, dateEnd time.Time) ([]*model.Event, error)
+	GetUserEventsRange(userID int, dateStart, dateEnd time.Time) ([]*model.Event, error)
+	CountEvents(userID int, dateStart, dateEnd time.Time, bucket model.Bucket) (map[string]int, error)
+	FindOverlapping(userID int, dateStart, dateEnd time.Time, excludeID int) ([]*model.Event, error)
+	GetTaggedEvents(tags []string) ([]*model.Event, error)
+	CountCalendarEvents(calendarID int) (int, error)
+	Transaction(fn func(tx Repository) error) error
+
+	TrashEvent(id int, deletedAt time.Time) error
+	GetTrash(userID int) ([]*model.TrashedEvent, error)
+	RestoreEvent(id int) (*model.Event, error)
+	PurgeEvent(id int) error
+	PurgeTrash(deletedBefore time.Time) ([]*model.TrashedEvent, error)
+}
+
+// RevisionRepository interface that holds history of event changes.
+type RevisionRepository interface {
+	AddRevision(revision *model.Revision) error
+	GetRevisions(eventID int) ([]*model.Revision, error)
+	GetRevision(eventID, number int) (*model.Revision, error)
+}
+
+// CalendarRepository interface that holds users' calendars.
+type CalendarRepository interface {
+	CreateCalendar(calendar *model.Calendar) error
+	GetCalendar(id int) (*model.Calendar, error)
+	GetCalendars(ownerID int) ([]*model.Calendar, error)
+	UpdateCalendar(calendar *model.Calendar) error
+	DeleteCalendar(id int) error
+	DefaultCalendar(ownerID int) (*model.Calendar, error)
+}
+
+// GrantRepository interface that holds access to calendars given to other users.
+type GrantRepository interface {
+	SaveGrant(grant *model.Grant) error
+	GetGrant(calendarID, userID int) (*model.Grant, error)
+	GetGrants(calendarID int) ([]*model.Grant, error)
+	GetUserGrants(userID int) ([]*model.Grant, error)
+	DeleteGrant(calendarID, userID int) error
+	DeleteCalendarGrants(calendarID int) error
+}
+
+// FeedRepository interface that holds public feed links of calendars.
+type FeedRepository interface {
+	CreateFeed(feed *model.Feed) error
+	GetFeed(id int) (*model.Feed, error)
+	GetFeeds(calendarID int) ([]*model.Feed, error)
+	UpdateFeed(feed *model.Feed) error
+	DeleteFeed(id int) error
+	DeleteCalendarFeeds(calendarID int) error
+	FindFeed(token string, at time.Time) (*model.Feed, error)
+	HitFeed(token string, at time.Time) error
+}
+
+// CategoryRepository interface that holds users' event categories.
+type CategoryRepository interface {
+	GetCategories(userID int) ([]*model.Category, error)
+	GetCategory(userID int, name string) (*model.Category, error)
+	SaveCategory(category *model.Category) error
+	DeleteCategory(userID int, name string) error
+}
+
+// UserRepository interface that holds functions for user settings.
+type UserRepository interface {
+	GetUser(id int) (*model.User, error)
+	SaveUser(user *model.User) error
 }
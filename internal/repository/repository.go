@@ -12,13 +12,18 @@ import (
 type MemoryRepository struct {
 	mu     sync.RWMutex
 	events map[int]*model.Event
+	trash  map[int]*model.TrashedEvent
+	tags   map[string]map[int]struct{}
 	nextID int
+	undo   *[]func()
 }
 
 // NewMemoryRepository creates new MemoryRepository.
 func NewMemoryRepository() *MemoryRepository {
 	return &MemoryRepository{
 		events: make(map[int]*model.Event),
+		trash:  make(map[int]*model.TrashedEvent),
+		tags:   make(map[string]map[int]struct{}),
 		nextID: 1,
 	}
 }
@@ -34,17 +39,42 @@ func (r *MemoryRepository) CreateEvent(event *model.Event) error {
 	defer r.mu.Unlock()
 
 	event.ID = r.nextID
-	r.events[event.ID] = &model.Event{
-		ID:     event.ID,
-		UserID: event.UserID,
-		Date:   event.Date,
-		Text:   event.Text,
-	}
+	r.remember(event.ID)
+	r.store(copyEvent(event))
 	r.nextID++
 
 	return nil
 }
 
+// SaveEvent stores event under its own id, replacing existing one.
+func (r *MemoryRepository) SaveEvent(event *model.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID == 0 {
+		return errors.New("event ID is required")
+	}
+
+	r.remember(event.ID)
+	r.store(copyEvent(event))
+	if event.ID >= r.nextID {
+		r.nextID = event.ID + 1
+	}
+	return nil
+}
+
+// GetEvent gets event by id.
+func (r *MemoryRepository) GetEvent(id int) (*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, exists := r.events[id]
+	if !exists {
+		return nil, errors.New("event not found")
+	}
+	return copyEvent(event), nil
+}
+
 // UpdateEvent updates event in the map
 func (r *MemoryRepository) UpdateEvent(id int, event *model.Event) error {
 	r.mu.Lock()
@@ -54,12 +84,10 @@ func (r *MemoryRepository) UpdateEvent(id int, event *model.Event) error {
 		return errors.New("event not found")
 	}
 
-	r.events[id] = &model.Event{
-		ID:     id,
-		UserID: event.UserID,
-		Date:   event.Date,
-		Text:   event.Text,
-	}
+	stored := copyEvent(event)
+	stored.ID = id
+	r.remember(id)
+	r.store(stored)
 
 	return nil
 }
@@ -73,18 +101,138 @@ func (r *MemoryRepository) DeleteEvent(id int) error {
 		return errors.New("event not found")
 	}
 
-	delete(r.events, id)
+	r.remember(id)
+	r.remove(id)
+	return nil
+}
+
+// Transaction runs fn against the repository and undoes its changes unless fn succeeds.
+// Other readers and writers wait until the transaction is finished.
+func (r *MemoryRepository) Transaction(fn func(tx Repository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var undo []func()
+	tx := &MemoryRepository{
+		events: r.events,
+		trash:  r.trash,
+		tags:   r.tags,
+		nextID: r.nextID,
+		undo:   &undo,
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	committed = true
+	r.nextID = tx.nextID
+	if r.undo != nil {
+		*r.undo = append(*r.undo, undo...)
+	}
+	return nil
+}
+
+// TrashEvent moves event to trash, hiding it from all queries.
+func (r *MemoryRepository) TrashEvent(id int, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, exists := r.events[id]
+	if !exists {
+		return errors.New("event not found")
+	}
+
+	r.remember(id)
+	r.remove(id)
+	r.trash[id] = &model.TrashedEvent{Event: *copyEvent(event), DeletedAt: deletedAt}
+	return nil
+}
+
+// GetTrash gets user's trashed events, most recently deleted first.
+func (r *MemoryRepository) GetTrash(userID int) ([]*model.TrashedEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var trashed []*model.TrashedEvent
+	for _, item := range r.trash {
+		if item.UserID == userID {
+			copied := *item
+			copied.Event = *copyEvent(&item.Event)
+			trashed = append(trashed, &copied)
+		}
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		if trashed[i].DeletedAt.Equal(trashed[j].DeletedAt) {
+			return trashed[i].ID > trashed[j].ID
+		}
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreEvent moves event from trash back under its old id.
+func (r *MemoryRepository) RestoreEvent(id int) (*model.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, exists := r.trash[id]
+	if !exists {
+		return nil, errors.New("event not found")
+	}
+
+	r.remember(id)
+	delete(r.trash, id)
+	r.store(copyEvent(&item.Event))
+	return copyEvent(&item.Event), nil
+}
+
+// PurgeEvent permanently removes event from trash.
+func (r *MemoryRepository) PurgeEvent(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.trash[id]; !exists {
+		return errors.New("event not found")
+	}
+
+	r.remember(id)
+	delete(r.trash, id)
 	return nil
 }
 
-// GetEventDay gets events for a provided day.
+// PurgeTrash permanently removes events deleted before provided time and returns them.
+func (r *MemoryRepository) PurgeTrash(deletedBefore time.Time) ([]*model.TrashedEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged []*model.TrashedEvent
+	for id, item := range r.trash {
+		if item.DeletedAt.Before(deletedBefore) {
+			r.remember(id)
+			delete(r.trash, id)
+			purged = append(purged, item)
+		}
+	}
+	return purged, nil
+}
+
+// GetEventDay gets events for a provided day, the day is taken in date's time zone.
 func (r *MemoryRepository) GetEventDay(date time.Time) ([]*model.Event, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var events []*model.Event
 	for _, event := range r.events {
-		if isSameDay(event.Date, date) {
+		if isSameDay(event.Date.In(date.Location()), date) {
 			events = append(events, event)
 		}
 	}
@@ -95,6 +243,16 @@ func (r *MemoryRepository) GetEventDay(date time.Time) ([]*model.Event, error) {
 
 // GetEventWeek gets events for a week.
 func (r *MemoryRepository) GetEventWeek(startDate, endDate time.Time) ([]*model.Event, error) {
+	return r.GetEventsRange(startDate, endDate)
+}
+
+// GetEventMonth gets events for a month.
+func (r *MemoryRepository) GetEventMonth(startDate, endDate time.Time) ([]*model.Event, error) {
+	return r.GetEventsRange(startDate, endDate)
+}
+
+// GetEventsRange gets events that start between provided dates, both inclusive.
+func (r *MemoryRepository) GetEventsRange(startDate, endDate time.Time) ([]*model.Event, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -110,9 +268,158 @@ func (r *MemoryRepository) GetEventWeek(startDate, endDate time.Time) ([]*model.
 	return events, nil
 }
 
-// GetEventMonth gets events for a month.
-func (r *MemoryRepository) GetEventMonth(startDate, endDate time.Time) ([]*model.Event, error) {
-	return r.GetEventWeek(startDate, endDate)
+// GetUserEventsRange gets user's events that overlap [startDate, endDate), including longer ones started earlier.
+func (r *MemoryRepository) GetUserEventsRange(userID int, startDate, endDate time.Time) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []*model.Event
+	for _, event := range r.events {
+		if event.UserID == userID && event.Overlaps(startDate, endDate) {
+			events = append(events, event)
+		}
+	}
+
+	sortEventsByDate(events)
+	return events, nil
+}
+
+// CountEvents counts user's events starting in [startDate, endDate) per bucket,
+// keyed by bucket's first day (YYYY-MM-DD) in startDate's time zone.
+func (r *MemoryRepository) CountEvents(userID int, startDate, endDate time.Time, bucket model.Bucket) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, event := range r.events {
+		if event.UserID != userID || event.Date.Before(startDate) || !event.Date.Before(endDate) {
+			continue
+		}
+		key := bucket.BucketStart(event.Date.In(startDate.Location())).Format("2006-01-02")
+		counts[key]++
+	}
+	return counts, nil
+}
+
+// FindOverlapping gets user's events that overlap [startDate, endDate), skipping excludeID.
+// Equal dates describe an instant, which overlaps events running at that moment.
+func (r *MemoryRepository) FindOverlapping(userID int, startDate, endDate time.Time, excludeID int) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []*model.Event
+	for _, event := range r.events {
+		if event.UserID != userID || event.ID == excludeID {
+			continue
+		}
+		if overlapsSpan(event, startDate, endDate) {
+			events = append(events, copyEvent(event))
+		}
+	}
+
+	sortEventsByDate(events)
+	return events, nil
+}
+
+// GetTaggedEvents gets events carrying every provided tag, looked up in tag index.
+func (r *MemoryRepository) GetTaggedEvents(tags []string) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(tags) == 0 {
+		return nil, errors.New("at least one tag is required")
+	}
+
+	smallest := r.tags[tags[0]]
+	for _, tag := range tags[1:] {
+		if len(r.tags[tag]) < len(smallest) {
+			smallest = r.tags[tag]
+		}
+	}
+
+	var events []*model.Event
+	for id := range smallest {
+		event := r.events[id]
+		if (model.TagFilter{Include: tags}).Matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	sortEventsByDate(events)
+	return events, nil
+}
+
+// CountCalendarEvents counts events in calendar, trashed ones are not counted.
+func (r *MemoryRepository) CountCalendarEvents(calendarID int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, event := range r.events {
+		if event.CalendarID == calendarID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// remember lets running transaction undo upcoming change of event by id, outside one it does nothing.
+// Caller must hold write lock.
+func (r *MemoryRepository) remember(id int) {
+	if r.undo == nil {
+		return
+	}
+	event, trashed := r.events[id], r.trash[id]
+	*r.undo = append(*r.undo, func() {
+		r.remove(id)
+		if event != nil {
+			r.store(event)
+		}
+		if trashed != nil {
+			r.trash[id] = trashed
+		} else {
+			delete(r.trash, id)
+		}
+	})
+}
+
+// store puts event into the map and tag index, replacing previous version.
+// Caller must hold write lock.
+func (r *MemoryRepository) store(event *model.Event) {
+	r.remove(event.ID)
+	r.events[event.ID] = event
+	for _, tag := range event.Tags {
+		if r.tags[tag] == nil {
+			r.tags[tag] = make(map[int]struct{})
+		}
+		r.tags[tag][event.ID] = struct{}{}
+	}
+}
+
+// remove deletes event from the map and tag index. Caller must hold write lock.
+func (r *MemoryRepository) remove(id int) {
+	event, exists := r.events[id]
+	if !exists {
+		return
+	}
+	for _, tag := range event.Tags {
+		delete(r.tags[tag], id)
+		if len(r.tags[tag]) == 0 {
+			delete(r.tags, tag)
+		}
+	}
+	delete(r.events, id)
+}
+
+// overlapsSpan checks if event overlaps span, treating zero length span as an instant.
+func overlapsSpan(event *model.Event, startDate, endDate time.Time) bool {
+	if !startDate.Equal(endDate) {
+		return event.Overlaps(startDate, endDate)
+	}
+	if event.Duration == 0 {
+		return event.Date.Equal(startDate)
+	}
+	return !startDate.Before(event.Date) && startDate.Before(event.End())
 }
 
 // isSameDay checks if provided days for a week/month is not the same dates
@@ -122,6 +429,18 @@ func isSameDay(date1, date2 time.Time) bool {
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
 
+// copyEvent returns a copy of event that does not share slices with the original.
+func copyEvent(event *model.Event) *model.Event {
+	stored := *event
+	if event.Reminders != nil {
+		stored.Reminders = append([]int(nil), event.Reminders...)
+	}
+	if event.Tags != nil {
+		stored.Tags = append([]string(nil), event.Tags...)
+	}
+	return &stored
+}
+
 // sortEventsByDate sorts events by date.
 func sortEventsByDate(events []*model.Event) {
 	sort.Slice(events, func(i, j int) bool {
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"l2.18/internal/model"
 	"testing"
 	"time"
@@ -129,6 +130,25 @@ func TestMemoryRepository_GetEventDay(t *testing.T) {
 	assert.Equal(t, "Afternoon Event", events[1].Text)
 }
 
+func TestMemoryRepository_GetEventDay_TimeZone(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2024-01-16 02:00 UTC is still January 15 in New York.
+	event := &model.Event{UserID: 1, Date: time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC), Text: "Late call"}
+	repo.CreateEvent(event)
+
+	events, err := repo.GetEventDay(time.Date(2024, 1, 15, 0, 0, 0, 0, newYork))
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = repo.GetEventDay(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
 func TestMemoryRepository_GetEventWeek(t *testing.T) {
 	repo := NewMemoryRepository()
 
@@ -149,6 +169,104 @@ func TestMemoryRepository_GetEventWeek(t *testing.T) {
 	assert.Len(t, events, 3)
 }
 
+func TestMemoryRepository_GetEventsRange(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(-time.Minute), Text: "Before"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(time.Hour), Text: "Inside", Reminders: []int{15}})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(3 * time.Hour), Text: "After"})
+
+	events, err := repo.GetEventsRange(start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Inside", events[0].Text)
+	assert.Equal(t, []int{15}, events[0].Reminders)
+}
+
+func TestMemoryRepository_SaveEvent(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	err := repo.SaveEvent(&model.Event{ID: 42, UserID: 1, Date: time.Now(), Text: "Imported"})
+	require.NoError(t, err)
+
+	saved, err := repo.GetEvent(42)
+	require.NoError(t, err)
+	assert.Equal(t, "Imported", saved.Text)
+
+	event := &model.Event{UserID: 1, Date: time.Now(), Text: "New"}
+	require.NoError(t, repo.CreateEvent(event))
+	assert.Equal(t, 43, event.ID)
+
+	assert.Error(t, repo.SaveEvent(&model.Event{UserID: 1, Text: "No ID"}))
+}
+
+func TestMemoryRepository_GetUserEventsRange(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(-time.Hour), Duration: 90, Text: "Spans start"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(-time.Hour), Duration: 60, Text: "Ends at start"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(time.Hour), Text: "Instant inside"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: start.Add(2 * time.Hour), Duration: 30, Text: "Starts at end"})
+	repo.CreateEvent(&model.Event{UserID: 2, Date: start, Duration: 30, Text: "Other user"})
+
+	events, err := repo.GetUserEventsRange(1, start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Spans start", events[0].Text)
+	assert.Equal(t, "Instant inside", events[1].Text)
+}
+
+func TestMemoryRepository_FindOverlapping(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	meeting := &model.Event{UserID: 1, Date: start, Duration: 60, Text: "Meeting"}
+	instant := &model.Event{UserID: 1, Date: start.Add(2 * time.Hour), Text: "Deadline"}
+	repo.CreateEvent(meeting)
+	repo.CreateEvent(instant)
+
+	events, err := repo.FindOverlapping(1, start.Add(30*time.Minute), start.Add(30*time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, meeting.ID, events[0].ID)
+
+	events, err = repo.FindOverlapping(1, start.Add(2*time.Hour), start.Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, instant.ID, events[0].ID)
+
+	events, err = repo.FindOverlapping(1, start, start.Add(3*time.Hour), meeting.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, instant.ID, events[0].ID)
+}
+
+func TestMemoryRepository_CountEvents(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	monday := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo.CreateEvent(&model.Event{UserID: 1, Date: monday, Text: "A"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: monday.Add(time.Hour), Text: "B"})
+	repo.CreateEvent(&model.Event{UserID: 1, Date: monday.AddDate(0, 0, 2), Text: "C"})
+	repo.CreateEvent(&model.Event{UserID: 2, Date: monday, Text: "Other user"})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	counts, err := repo.CountEvents(1, start, end, model.BucketDay)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"2024-01-15": 2, "2024-01-17": 1}, counts)
+
+	counts, err = repo.CountEvents(1, start, end, model.BucketWeek)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"2024-01-15": 3}, counts)
+}
+
 func TestMemoryRepository_ConcurrentAccess(t *testing.T) {
 	repo := NewMemoryRepository()
 
@@ -174,3 +292,204 @@ func TestMemoryRepository_ConcurrentAccess(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, events, 10)
 }
+
+func TestMemoryRepository_Transaction(t *testing.T) {
+	repo := NewMemoryRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 1, Date: date, Text: "Kept"}))
+
+	err := repo.Transaction(func(tx Repository) error {
+		require.NoError(t, tx.CreateEvent(&model.Event{UserID: 1, Date: date, Text: "Rolled back"}))
+		require.NoError(t, tx.DeleteEvent(1))
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	event, err := repo.GetEvent(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Kept", event.Text)
+	_, err = repo.GetEvent(2)
+	assert.Error(t, err, "rolled back event is not stored")
+
+	err = repo.Transaction(func(tx Repository) error {
+		created := &model.Event{UserID: 1, Date: date, Text: "Committed"}
+		if err := tx.CreateEvent(created); err != nil {
+			return err
+		}
+		assert.Equal(t, 2, created.ID)
+		return tx.DeleteEvent(1)
+	})
+	require.NoError(t, err)
+
+	_, err = repo.GetEvent(1)
+	assert.Error(t, err)
+	event, err = repo.GetEvent(2)
+	require.NoError(t, err)
+	assert.Equal(t, "Committed", event.Text)
+}
+
+func TestMemoryRepository_Transaction_Undo(t *testing.T) {
+	repo := NewMemoryRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	kept := &model.Event{UserID: 1, Date: date, Text: "Kept", Tags: []string{"work"}}
+	trashed := &model.Event{UserID: 1, Date: date, Text: "Trashed"}
+	require.NoError(t, repo.CreateEvent(kept))
+	require.NoError(t, repo.CreateEvent(trashed))
+	require.NoError(t, repo.TrashEvent(trashed.ID, date))
+
+	err := repo.Transaction(func(tx Repository) error {
+		require.NoError(t, tx.UpdateEvent(kept.ID, &model.Event{UserID: 1, Date: date, Text: "Changed", Tags: []string{"home"}}))
+		require.NoError(t, tx.TrashEvent(kept.ID, date))
+		require.NoError(t, tx.PurgeEvent(kept.ID))
+		_, err := tx.RestoreEvent(trashed.ID)
+		require.NoError(t, err)
+		return tx.Transaction(func(nested Repository) error {
+			return nested.DeleteEvent(trashed.ID)
+		})
+	})
+	require.NoError(t, err, "nested transaction commits with the outer one")
+	_, err = repo.GetEvent(trashed.ID)
+	assert.Error(t, err)
+
+	require.NoError(t, repo.CreateEvent(kept))
+	err = repo.Transaction(func(tx Repository) error {
+		require.NoError(t, tx.UpdateEvent(kept.ID, &model.Event{UserID: 1, Date: date, Text: "Changed", Tags: []string{"home"}}))
+		require.NoError(t, tx.TrashEvent(kept.ID, date))
+		_, err := tx.PurgeTrash(date.Add(time.Second))
+		require.NoError(t, err)
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	event, err := repo.GetEvent(kept.ID)
+	require.NoError(t, err, "rolled back changes are undone in reverse order")
+	assert.Equal(t, "Kept", event.Text)
+	events, err := repo.GetTaggedEvents([]string{"work"})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	events, err = repo.GetTaggedEvents([]string{"home"})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	trash, err := repo.GetTrash(1)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+}
+
+func TestMemoryRepository_Trash(t *testing.T) {
+	repo := NewMemoryRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	deletedAt := date.Add(time.Hour)
+
+	event := &model.Event{UserID: 1, Date: date, Text: "Dentist"}
+	require.NoError(t, repo.CreateEvent(event))
+	require.NoError(t, repo.TrashEvent(event.ID, deletedAt))
+	assert.Error(t, repo.TrashEvent(event.ID, deletedAt))
+
+	_, err := repo.GetEvent(event.ID)
+	assert.Error(t, err, "trashed event is hidden")
+	events, err := repo.GetEventDay(date)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	trashed, err := repo.GetTrash(1)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, "Dentist", trashed[0].Text)
+	assert.Equal(t, deletedAt, trashed[0].DeletedAt)
+
+	restored, err := repo.RestoreEvent(event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, restored.ID)
+	_, err = repo.GetEvent(event.ID)
+	assert.NoError(t, err)
+
+	require.NoError(t, repo.TrashEvent(event.ID, deletedAt))
+	purged, err := repo.PurgeTrash(deletedAt)
+	require.NoError(t, err)
+	assert.Empty(t, purged, "only events deleted before the cutoff are purged")
+	purged, err = repo.PurgeTrash(deletedAt.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, event.ID, purged[0].ID)
+
+	_, err = repo.RestoreEvent(event.ID)
+	assert.Error(t, err)
+	assert.Error(t, repo.PurgeEvent(event.ID))
+}
+
+func TestMemoryRevisionRepository(t *testing.T) {
+	repo := NewMemoryRevisionRepository()
+
+	first := &model.Revision{EventID: 1, Action: model.RevisionCreate, Event: model.Event{ID: 1, Reminders: []int{15}}}
+	require.NoError(t, repo.AddRevision(first))
+	require.NoError(t, repo.AddRevision(&model.Revision{EventID: 1, Action: model.RevisionUpdate}))
+	assert.Equal(t, 1, first.Number)
+	assert.Error(t, repo.AddRevision(&model.Revision{}))
+
+	first.Event.Reminders[0] = 30
+	revisions, err := repo.GetRevisions(1)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, []int{15}, revisions[0].Event.Reminders, "stored revision is a copy")
+	assert.Equal(t, 2, revisions[1].Number)
+
+	revision, err := repo.GetRevision(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, model.RevisionUpdate, revision.Action)
+
+	_, err = repo.GetRevision(1, 3)
+	assert.Error(t, err)
+	_, err = repo.GetRevisions(2)
+	assert.Error(t, err)
+}
+
+func TestMemoryRepository_GetTaggedEvents(t *testing.T) {
+	repo := NewMemoryRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	work := &model.Event{UserID: 1, Date: date, Text: "Standup", Tags: []string{"work", "daily"}}
+	review := &model.Event{UserID: 1, Date: date.Add(time.Hour), Text: "Review", Tags: []string{"work"}}
+	gym := &model.Event{UserID: 1, Date: date, Text: "Gym", Tags: []string{"personal"}}
+	for _, event := range []*model.Event{review, work, gym} {
+		require.NoError(t, repo.CreateEvent(event))
+	}
+
+	events, err := repo.GetTaggedEvents([]string{"work"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, work.ID, events[0].ID, "sorted by date")
+
+	events, err = repo.GetTaggedEvents([]string{"work", "daily"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, work.ID, events[0].ID)
+
+	review.Tags = []string{"personal"}
+	require.NoError(t, repo.UpdateEvent(review.ID, review))
+	require.NoError(t, repo.TrashEvent(work.ID, date))
+
+	events, err = repo.GetTaggedEvents([]string{"work"})
+	require.NoError(t, err)
+	assert.Empty(t, events, "index follows updates and trash")
+
+	events, err = repo.GetTaggedEvents([]string{"personal"})
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	_, err = repo.RestoreEvent(work.ID)
+	require.NoError(t, err)
+	events, err = repo.GetTaggedEvents([]string{"daily"})
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "restored event is indexed again")
+
+	err = repo.Transaction(func(tx Repository) error {
+		if err := tx.DeleteEvent(gym.ID); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+	events, err = repo.GetTaggedEvents([]string{"personal"})
+	require.NoError(t, err)
+	assert.Len(t, events, 2, "rolled back transaction keeps index")
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sync"
+)
+
+// MemoryRevisionRepository struct holds revisions of events.
+type MemoryRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[int][]*model.Revision
+}
+
+// NewMemoryRevisionRepository creates new MemoryRevisionRepository.
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{
+		revisions: make(map[int][]*model.Revision),
+	}
+}
+
+// AddRevision appends revision to event's history and numbers it.
+func (r *MemoryRevisionRepository) AddRevision(revision *model.Revision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if revision.EventID == 0 {
+		return errors.New("event ID is required")
+	}
+
+	revision.Number = len(r.revisions[revision.EventID]) + 1
+	r.revisions[revision.EventID] = append(r.revisions[revision.EventID], copyRevision(revision))
+	return nil
+}
+
+// GetRevisions gets event's revisions, oldest first.
+func (r *MemoryRevisionRepository) GetRevisions(eventID int) ([]*model.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history, exists := r.revisions[eventID]
+	if !exists {
+		return nil, errors.New("event not found")
+	}
+
+	revisions := make([]*model.Revision, len(history))
+	for i, revision := range history {
+		revisions[i] = copyRevision(revision)
+	}
+	return revisions, nil
+}
+
+// GetRevision gets event's revision by number.
+func (r *MemoryRevisionRepository) GetRevision(eventID, number int) (*model.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.revisions[eventID]
+	if number < 1 || number > len(history) {
+		return nil, errors.New("revision not found")
+	}
+	return copyRevision(history[number-1]), nil
+}
+
+// copyRevision returns a copy of revision that does not share slices with the original.
+func copyRevision(revision *model.Revision) *model.Revision {
+	copied := *revision
+	copied.Event = *copyEvent(&revision.Event)
+	copied.Changes = append([]model.FieldChange(nil), revision.Changes...)
+	return &copied
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"l2.18/internal/model"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// prefixWeight lowers score of terms matched only by prefix.
+const prefixWeight = 0.5
+
+// SearchQuery describes full-text search, zero UserID, Start and End match everything.
+type SearchQuery struct {
+	Text   string
+	UserID int
+	Start  time.Time
+	End    time.Time
+}
+
+// SearchHit is event matching search query.
+type SearchHit struct {
+	ID    int
+	Date  time.Time
+	Score float64
+}
+
+// indexedDoc is what index knows about event.
+type indexedDoc struct {
+	userID int
+	date   time.Time
+	terms  map[string]int
+	length int
+}
+
+// SearchIndex is in-memory inverted index over event texts.
+type SearchIndex struct {
+	mu       sync.Mutex
+	postings map[string]map[int]int
+	docs     map[int]*indexedDoc
+	terms    []string
+	sorted   bool
+}
+
+// NewSearchIndex creates new SearchIndex.
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		postings: make(map[string]map[int]int),
+		docs:     make(map[int]*indexedDoc),
+	}
+}
+
+// Tokenize splits text into lowercase words of letters and digits, any script.
+func Tokenize(text string) []string {
+	text = strings.NewReplacer("ё", "е", "Ё", "Е").Replace(text)
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Index adds event to index, replacing its previous version.
+func (i *SearchIndex) Index(event *model.Event) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(event.ID)
+
+	doc := &indexedDoc{
+		userID: event.UserID,
+		date:   event.Date,
+		terms:  make(map[string]int),
+	}
+	for _, term := range Tokenize(event.Text) {
+		doc.terms[term]++
+		doc.length++
+	}
+	for term, count := range doc.terms {
+		if i.postings[term] == nil {
+			i.postings[term] = make(map[int]int)
+			i.sorted = false
+		}
+		i.postings[term][event.ID] = count
+	}
+	i.docs[event.ID] = doc
+}
+
+// Remove removes event from index.
+func (i *SearchIndex) Remove(id int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// remove removes event from index, mu must be held.
+func (i *SearchIndex) remove(id int) {
+	doc, exists := i.docs[id]
+	if !exists {
+		return
+	}
+	for term := range doc.terms {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+			i.sorted = false
+		}
+	}
+	delete(i.docs, id)
+}
+
+// Search finds events containing every query word, the last one also as a prefix.
+// Hits are ordered by TF-IDF relevance, newer events first on equal score.
+func (i *SearchIndex) Search(query SearchQuery) []SearchHit {
+	words := Tokenize(query.Text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.sorted {
+		i.terms = i.terms[:0]
+		for term := range i.postings {
+			i.terms = append(i.terms, term)
+		}
+		sort.Strings(i.terms)
+		i.sorted = true
+	}
+
+	var scores map[int]float64
+	for n, word := range words {
+		wordScores := make(map[int]float64)
+		i.score(wordScores, word, 1)
+		if n == len(words)-1 {
+			for _, term := range i.withPrefix(word) {
+				if term != word {
+					i.score(wordScores, term, prefixWeight)
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for id, score := range scores {
+			if wordScore, ok := wordScores[id]; ok {
+				scores[id] = score + wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for id, score := range scores {
+		doc := i.docs[id]
+		if query.UserID != 0 && doc.userID != query.UserID {
+			continue
+		}
+		if !query.Start.IsZero() && doc.date.Before(query.Start) {
+			continue
+		}
+		if !query.End.IsZero() && !doc.date.Before(query.End) {
+			continue
+		}
+		hits = append(hits, SearchHit{ID: id, Date: doc.date, Score: score})
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		if !hits[a].Date.Equal(hits[b].Date) {
+			return hits[a].Date.After(hits[b].Date)
+		}
+		return hits[a].ID > hits[b].ID
+	})
+	return hits
+}
+
+// score adds weighted TF-IDF of term to scores of documents containing it, keeping the best per document.
+func (i *SearchIndex) score(scores map[int]float64, term string, weight float64) {
+	postings := i.postings[term]
+	if len(postings) == 0 {
+		return
+	}
+	idf := math.Log(1 + float64(len(i.docs))/float64(len(postings)))
+	for id, count := range postings {
+		score := weight * idf * float64(count) / float64(i.docs[id].length)
+		if score > scores[id] {
+			scores[id] = score
+		}
+	}
+}
+
+// withPrefix returns indexed terms starting with prefix, mu must be held and terms sorted.
+func (i *SearchIndex) withPrefix(prefix string) []string {
+	start := sort.SearchStrings(i.terms, prefix)
+	end := start
+	for end < len(i.terms) && strings.HasPrefix(i.terms[end], prefix) {
+		end++
+	}
+	return i.terms[start:end]
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"l2.18/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hitIDs(hits []SearchHit) []int {
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"запись", "к", "dentist", "у", "стоматолога", "2024"},
+		Tokenize("Запись к Dentist'у (стоматолога) — 2024!"))
+	assert.Equal(t, []string{"елка"}, Tokenize("Ёлка"))
+	assert.Empty(t, Tokenize(" ,.- "))
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	index := NewSearchIndex()
+	jan := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	events := []*model.Event{
+		{ID: 1, UserID: 1, Date: jan, Text: "Dentist appointment"},
+		{ID: 2, UserID: 1, Date: jan.AddDate(0, 1, 0), Text: "Dentist"},
+		{ID: 3, UserID: 1, Date: jan.AddDate(0, 2, 0), Text: "Запись к стоматологу"},
+		{ID: 4, UserID: 2, Date: jan, Text: "Dentist"},
+		{ID: 5, UserID: 1, Date: jan, Text: "Team sync with dentistry vendor"},
+	}
+	for _, event := range events {
+		index.Index(event)
+	}
+
+	hits := index.Search(SearchQuery{Text: "dentist", UserID: 1})
+	assert.Equal(t, []int{2, 1, 5}, hitIDs(hits), "exact short text first, prefix match last")
+
+	hits = index.Search(SearchQuery{Text: "DENTIST appoint", UserID: 1})
+	assert.Equal(t, []int{1}, hitIDs(hits), "every word must match")
+
+	hits = index.Search(SearchQuery{Text: "стомат"})
+	assert.Equal(t, []int{3}, hitIDs(hits))
+
+	hits = index.Search(SearchQuery{Text: "dentist", Start: jan.AddDate(0, 0, 1), End: jan.AddDate(0, 3, 0)})
+	assert.Equal(t, []int{2}, hitIDs(hits))
+
+	hits = index.Search(SearchQuery{Text: "dentist"})
+	require.Len(t, hits, 4)
+	assert.Equal(t, []int{2, 4}, hitIDs(hits[:2]), "newer event first on equal score")
+
+	index.Index(&model.Event{ID: 2, UserID: 1, Date: jan, Text: "Orthodontist"})
+	hits = index.Search(SearchQuery{Text: "dentist", UserID: 1})
+	assert.Equal(t, []int{1, 5}, hitIDs(hits), "reindexed event drops old words")
+
+	index.Remove(1)
+	index.Remove(42)
+	hits = index.Search(SearchQuery{Text: "appointment"})
+	assert.Empty(t, hits)
+	assert.Empty(t, index.Search(SearchQuery{Text: "?"}))
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"l2.18/internal/model"
+	"sync"
+)
+
+// MemoryUserRepository struct holds user settings.
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[int]*model.User
+}
+
+// NewMemoryUserRepository creates new MemoryUserRepository.
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users: make(map[int]*model.User),
+	}
+}
+
+// GetUser gets user settings by id.
+func (r *MemoryUserRepository) GetUser(id int) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return nil, errors.New("user not found")
+	}
+	stored := *user
+	return &stored, nil
+}
+
+// SaveUser creates or replaces user settings.
+func (r *MemoryUserRepository) SaveUser(user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
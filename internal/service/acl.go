@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"strings"
+)
+
+// WithGrants lets calendar owners share calendars with other users.
+// Roles are checked against user ids the service is given, it does not authenticate them.
+func WithGrants(grants repository.GrantRepository) Option {
+	return func(s *EventService) {
+		s.grants = grants
+	}
+}
+
+// GetGrants gets users calendar is shared with, only its owner may see them.
+func (s *EventService) GetGrants(userID, calendarID int) ([]*model.Grant, error) {
+	if _, err := s.authorize("get_grants", userID, calendarID, model.PermissionManage); err != nil {
+		return nil, err
+	}
+	if s.grants == nil {
+		return []*model.Grant{}, nil
+	}
+
+	grants, err := s.grants.GetGrants(calendarID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "get_grants",
+			Message:   err.Error(),
+		}
+	}
+	return grants, nil
+}
+
+// GrantAccess gives another user a role in calendar owned by userID, replacing previous role.
+func (s *EventService) GrantAccess(userID int, grant *model.Grant) error {
+	calendar, err := s.authorize("grant_access", userID, grant.CalendarID, model.PermissionManage)
+	if err != nil {
+		return err
+	}
+	if s.grants == nil {
+		return errors.InternalError{
+			Operation: "grant_access",
+			Message:   "sharing is not configured",
+		}
+	}
+	if grant.UserID == 0 || grant.UserID == calendar.OwnerID {
+		return errors.ValidationError{
+			Field:   "user_id",
+			Message: "access can only be granted to another user",
+		}
+	}
+	if !grant.Role.Grantable() {
+		return errors.ValidationError{
+			Field:   "role",
+			Message: "role must be one of editor, viewer, freebusy",
+		}
+	}
+
+	if err := s.grants.SaveGrant(grant); err != nil {
+		return errors.InternalError{
+			Operation: "grant_access",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// RevokeAccess takes away grantee's role in calendar. The owner may revoke anybody, grantees only themselves.
+func (s *EventService) RevokeAccess(userID, calendarID, granteeID int) error {
+	permission := model.PermissionManage
+	if userID == granteeID {
+		permission = model.PermissionFreeBusy
+	}
+	if _, err := s.authorize("revoke_access", userID, calendarID, permission); err != nil {
+		return err
+	}
+	if s.grants == nil {
+		return errors.BusinessError{
+			Operation: "revoke_access",
+			Message:   "grant not found",
+		}
+	}
+
+	if err := s.grants.DeleteGrant(calendarID, granteeID); err != nil {
+		return errors.BusinessError{
+			Operation: "revoke_access",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// role returns user's role in calendar, empty if user has no access.
+func (s *EventService) role(userID int, calendar *model.Calendar) model.Role {
+	if calendar.OwnerID == userID {
+		return model.RoleOwner
+	}
+	if s.grants == nil {
+		return ""
+	}
+	grant, err := s.grants.GetGrant(calendar.ID, userID)
+	if err != nil {
+		return ""
+	}
+	return grant.Role
+}
+
+// authorize checks that user has permission in calendar and returns the calendar with user's role.
+// Calendars the user has no role in look missing, a role lacking the permission is forbidden.
+func (s *EventService) authorize(operation string, userID, calendarID int, permission model.Permission) (*model.Calendar, error) {
+	if err := s.checkCalendars(userID); err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendars.GetCalendar(calendarID)
+	if err != nil {
+		return nil, calendarError(operation, err)
+	}
+	calendar.Role = s.role(userID, calendar)
+	if calendar.Role == "" {
+		return nil, errors.BusinessError{
+			Operation: operation,
+			Message:   "calendar not found",
+		}
+	}
+	if !calendar.Role.Allows(permission) {
+		return nil, errors.ForbiddenError{
+			Operation: operation,
+			Message:   fmt.Sprintf("%s role has no %s permission", calendar.Role, permission),
+		}
+	}
+	return calendar, nil
+}
+
+// freeBusyCalendars returns calendars of owner whose free/busy time reader may see, nil when reader sees all
+// of them. Zero reader and the owner see all calendars, readers without any role in them are forbidden.
+func (s *EventService) freeBusyCalendars(operation string, reader, ownerID int) (map[int]bool, error) {
+	if reader == 0 || reader == ownerID {
+		return nil, nil
+	}
+
+	forbidden := errors.ForbiddenError{
+		Operation: operation,
+		Message:   fmt.Sprintf("no access to free/busy time of user %d", ownerID),
+	}
+	if s.calendars == nil {
+		return nil, forbidden
+	}
+	calendars, err := s.calendars.GetCalendars(ownerID)
+	if err != nil {
+		return nil, calendarError(operation, err)
+	}
+	visible := make(map[int]bool)
+	for _, calendar := range calendars {
+		if s.role(reader, calendar).Allows(model.PermissionFreeBusy) {
+			visible[calendar.ID] = true
+		}
+	}
+	if len(visible) == 0 {
+		return nil, forbidden
+	}
+	return visible, nil
+}
+
+// authorizeEvent checks that user has permission in event's calendar, owners always have it.
+// Events of calendars the user has no role in look missing.
+func (s *EventService) authorizeEvent(operation string, userID int, event *model.Event, permission model.Permission) error {
+	if event.UserID == userID {
+		return nil
+	}
+
+	notFound := errors.BusinessError{
+		Operation: operation,
+		Message:   "event not found",
+	}
+	if s.calendars == nil || event.CalendarID == 0 {
+		return notFound
+	}
+	_, err := s.authorize(operation, userID, event.CalendarID, permission)
+	if e, ok := err.(errors.BusinessError); ok && e.Message == "calendar not found" {
+		return notFound
+	}
+	return err
+}
+
+// writer returns user making a change, which must be known for access to be checked.
+func (s *EventService) writer(operation string) (int, error) {
+	if s.actor.UserID == 0 {
+		return 0, errors.ValidationError{
+			Field:   "user_id",
+			Message: "acting user is required to " + strings.ReplaceAll(operation, "_", " "),
+		}
+	}
+	return s.actor.UserID, nil
+}
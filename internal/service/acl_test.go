@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole_Allows(t *testing.T) {
+	permissions := []model.Permission{model.PermissionFreeBusy, model.PermissionRead, model.PermissionWrite, model.PermissionManage}
+	tests := []struct {
+		role    model.Role
+		allowed []bool
+	}{
+		{model.RoleOwner, []bool{true, true, true, true}},
+		{model.RoleEditor, []bool{true, true, true, false}},
+		{model.RoleViewer, []bool{true, true, false, false}},
+		{model.RoleFreeBusy, []bool{true, false, false, false}},
+		{"", []bool{false, false, false, false}},
+		{"admin", []bool{false, false, false, false}},
+	}
+	for _, tt := range tests {
+		for i, permission := range permissions {
+			assert.Equal(t, tt.allowed[i], tt.role.Allows(permission), "%q role, %s permission", tt.role, permission)
+		}
+	}
+
+	assert.False(t, model.RoleOwner.Grantable())
+	assert.True(t, model.RoleEditor.Grantable())
+	assert.True(t, model.RoleViewer.Grantable())
+	assert.True(t, model.RoleFreeBusy.Grantable())
+	assert.False(t, model.Role("admin").Grantable())
+}
+
+// aclFixture is a team calendar of user 1 shared with users 2, 3, 4 and 6, user 5 has no role in it.
+type aclFixture struct {
+	service  *EventService
+	calendar *model.Calendar
+	event    *model.Event
+}
+
+var aclUsers = map[model.Role]int{
+	model.RoleOwner:    1,
+	model.RoleEditor:   2,
+	model.RoleViewer:   3,
+	model.RoleFreeBusy: 4,
+	"":                 5,
+}
+
+func newACLFixture(t *testing.T) *aclFixture {
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithCalendars(repository.NewMemoryCalendarRepository()),
+		WithGrants(repository.NewMemoryGrantRepository()),
+		WithRevisions(repository.NewMemoryRevisionRepository()),
+		WithSearch(repository.NewSearchIndex()))
+
+	team := &model.Calendar{OwnerID: 1, Name: "Team"}
+	require.NoError(t, service.CreateCalendar(team))
+	event := &model.Event{UserID: 1, CalendarID: team.ID, Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Duration: 15, Text: "Team standup"}
+	require.NoError(t, service.As(model.Actor{UserID: 1}).CreateEvent(event))
+
+	for userID, role := range map[int]model.Role{2: model.RoleEditor, 3: model.RoleViewer, 4: model.RoleFreeBusy, 6: model.RoleViewer} {
+		require.NoError(t, service.GrantAccess(1, &model.Grant{CalendarID: team.ID, UserID: userID, Role: role}))
+	}
+	return &aclFixture{service: service, calendar: team, event: event}
+}
+
+// outcome classifies error of an operation.
+func outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	if _, ok := err.(errors.ForbiddenError); ok {
+		return "forbidden"
+	}
+	if strings.Contains(err.Error(), "not found") {
+		return "missing"
+	}
+	return err.Error()
+}
+
+func TestEventService_ACL(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	operations := map[string]func(f *aclFixture, userID int) error{
+		"get event": func(f *aclFixture, userID int) error {
+			_, err := f.service.GetUserEvent(userID, f.event.ID)
+			return err
+		},
+		"list events": func(f *aclFixture, userID int) error {
+			events, err := f.service.InCalendars([]int{f.calendar.ID}).GetUserEvents(userID, date, date.AddDate(0, 0, 1))
+			if err == nil && len(events) != 1 {
+				return fmt.Errorf("got %d events", len(events))
+			}
+			return err
+		},
+		"search": func(f *aclFixture, userID int) error {
+			results, err := f.service.InCalendars([]int{f.calendar.ID}).Search(SearchQuery{UserID: userID, Text: "standup"})
+			if err == nil && len(results) != 1 {
+				return fmt.Errorf("got %d results", len(results))
+			}
+			return err
+		},
+		"get revisions": func(f *aclFixture, userID int) error {
+			_, err := f.service.GetRevisions(userID, f.event.ID)
+			return err
+		},
+		"free/busy": func(f *aclFixture, userID int) error {
+			freeBusy, err := f.service.GetCalendarFreeBusy(userID, f.calendar.ID, date, date.AddDate(0, 0, 1))
+			if err == nil && len(freeBusy.Busy) != 1 {
+				return fmt.Errorf("got %d busy intervals", len(freeBusy.Busy))
+			}
+			return err
+		},
+		"user free/busy": func(f *aclFixture, userID int) error {
+			users, err := f.service.As(model.Actor{UserID: userID}).GetFreeBusy([]int{1}, date, date.AddDate(0, 0, 1))
+			if err == nil && len(users[0].Busy) != 1 {
+				return fmt.Errorf("got %d busy intervals", len(users[0].Busy))
+			}
+			return err
+		},
+		"find slots": func(f *aclFixture, userID int) error {
+			slots, err := NewSchedulingService(f.service).FindSlots(userID, model.SlotRequest{
+				Attendees: []int{1},
+				Duration:  15,
+				Start:     f.event.Date,
+				End:       f.event.End(),
+			})
+			if err == nil && len(slots) != 0 {
+				return fmt.Errorf("got %d slots while owner is busy", len(slots))
+			}
+			return err
+		},
+		"get calendar": func(f *aclFixture, userID int) error {
+			_, err := f.service.GetCalendar(userID, f.calendar.ID)
+			return err
+		},
+		"create event": func(f *aclFixture, userID int) error {
+			event := &model.Event{UserID: userID, CalendarID: f.calendar.ID, Date: date, Text: "Retro"}
+			if err := f.service.As(model.Actor{UserID: userID}).CreateEvent(event); err != nil {
+				return err
+			}
+			if event.UserID != 1 {
+				return fmt.Errorf("event belongs to user %d", event.UserID)
+			}
+			return nil
+		},
+		"update event": func(f *aclFixture, userID int) error {
+			event := *f.event
+			event.Text = "Team sync"
+			return f.service.As(model.Actor{UserID: userID}).UpdateEvent(&event)
+		},
+		"delete event": func(f *aclFixture, userID int) error {
+			return f.service.As(model.Actor{UserID: userID}).DeleteEvent(f.event.ID)
+		},
+		"rollback event": func(f *aclFixture, userID int) error {
+			_, err := f.service.RollbackEvent(userID, f.event.ID, 1)
+			return err
+		},
+		"update calendar": func(f *aclFixture, userID int) error {
+			return f.service.UpdateCalendar(userID, &model.Calendar{ID: f.calendar.ID, Name: "Squad"})
+		},
+		"delete calendar": func(f *aclFixture, userID int) error {
+			require.NoError(t, f.service.As(model.Actor{UserID: 1}).DeleteEvent(f.event.ID))
+			return f.service.DeleteCalendar(userID, f.calendar.ID)
+		},
+		"get grants": func(f *aclFixture, userID int) error {
+			_, err := f.service.GetGrants(userID, f.calendar.ID)
+			return err
+		},
+		"grant access": func(f *aclFixture, userID int) error {
+			return f.service.GrantAccess(userID, &model.Grant{CalendarID: f.calendar.ID, UserID: 7, Role: model.RoleViewer})
+		},
+		"revoke access": func(f *aclFixture, userID int) error {
+			return f.service.RevokeAccess(userID, f.calendar.ID, 6)
+		},
+		"leave calendar": func(f *aclFixture, userID int) error {
+			return f.service.RevokeAccess(userID, f.calendar.ID, userID)
+		},
+	}
+
+	// Expected outcomes for owner, editor, viewer, free/busy-only and no role.
+	expected := map[string][5]string{
+		"get event":       {"ok", "ok", "ok", "forbidden", "missing"},
+		"list events":     {"ok", "ok", "ok", "forbidden", "missing"},
+		"search":          {"ok", "ok", "ok", "forbidden", "missing"},
+		"get revisions":   {"ok", "ok", "ok", "forbidden", "missing"},
+		"free/busy":       {"ok", "ok", "ok", "ok", "missing"},
+		"user free/busy":  {"ok", "ok", "ok", "ok", "forbidden"},
+		"find slots":      {"ok", "ok", "ok", "ok", "forbidden"},
+		"get calendar":    {"ok", "ok", "ok", "ok", "missing"},
+		"create event":    {"ok", "ok", "forbidden", "forbidden", "missing"},
+		"update event":    {"ok", "ok", "forbidden", "forbidden", "missing"},
+		"delete event":    {"ok", "ok", "forbidden", "forbidden", "missing"},
+		"rollback event":  {"ok", "ok", "forbidden", "forbidden", "missing"},
+		"update calendar": {"ok", "forbidden", "forbidden", "forbidden", "missing"},
+		"delete calendar": {"ok", "forbidden", "forbidden", "forbidden", "missing"},
+		"get grants":      {"ok", "forbidden", "forbidden", "forbidden", "missing"},
+		"grant access":    {"ok", "forbidden", "forbidden", "forbidden", "missing"},
+		"revoke access":   {"ok", "forbidden", "forbidden", "forbidden", "missing"},
+		"leave calendar":  {"missing", "ok", "ok", "ok", "missing"},
+	}
+	roles := []model.Role{model.RoleOwner, model.RoleEditor, model.RoleViewer, model.RoleFreeBusy, ""}
+
+	require.Len(t, expected, len(operations))
+	for name, operation := range operations {
+		for i, role := range roles {
+			t.Run(fmt.Sprintf("%s/%s", name, role), func(t *testing.T) {
+				f := newACLFixture(t)
+				assert.Equal(t, expected[name][i], outcome(operation(f, aclUsers[role])))
+			})
+		}
+	}
+}
+
+func TestEventService_Sharing(t *testing.T) {
+	f := newACLFixture(t)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	calendars, err := f.service.GetCalendars(3)
+	require.NoError(t, err)
+	require.Len(t, calendars, 2, "own default calendar and shared one")
+	assert.Equal(t, model.RoleOwner, calendars[0].Role)
+	assert.Equal(t, f.calendar.ID, calendars[1].ID)
+	assert.Equal(t, model.RoleViewer, calendars[1].Role)
+
+	owner := f.service.As(model.Actor{UserID: 1})
+	require.NoError(t, owner.CreateEvent(&model.Event{UserID: 1, Date: date.Add(15 * time.Hour), Duration: 60, Text: "Private"}))
+	users, err := f.service.As(model.Actor{UserID: 4}).GetFreeBusy([]int{1}, date, date.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []model.Interval{{Start: f.event.Date, End: f.event.End()}}, users[0].Busy, "only shared calendars count")
+
+	events, err := f.service.GetUserEvents(3, date, date.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Empty(t, events, "shared calendars are only read when selected")
+
+	editor := f.service.As(model.Actor{UserID: 2})
+	overlap := &model.Event{UserID: 2, CalendarID: f.calendar.ID, Date: f.event.Date, Duration: 30, Text: "Overlap"}
+	_, err = editor.CreateEventChecked(overlap, ConflictReject)
+	assert.ErrorContains(t, err, "overlaps", "checked against calendar owner's events")
+	_, err = f.service.As(model.Actor{UserID: 1}).CreateEventChecked(overlap, ConflictReject)
+	assert.ErrorContains(t, err, "overlaps", "same outcome for the owner")
+
+	assert.Error(t, f.service.GrantAccess(1, &model.Grant{CalendarID: f.calendar.ID, UserID: 1, Role: model.RoleViewer}))
+	assert.Error(t, f.service.GrantAccess(1, &model.Grant{CalendarID: f.calendar.ID, UserID: 3, Role: model.RoleOwner}))
+	require.NoError(t, f.service.GrantAccess(1, &model.Grant{CalendarID: f.calendar.ID, UserID: 3, Role: model.RoleEditor}))
+	assert.ErrorContains(t, f.service.DeleteEvent(f.event.ID), "acting user is required", "writes without actor are refused")
+	require.NoError(t, f.service.As(model.Actor{UserID: 3}).DeleteEvent(f.event.ID), "upgraded to editor")
+
+	require.NoError(t, f.service.RevokeAccess(1, f.calendar.ID, 3))
+	_, err = f.service.GetCalendar(3, f.calendar.ID)
+	assert.ErrorContains(t, err, "calendar not found")
+	assert.ErrorContains(t, f.service.RevokeAccess(1, f.calendar.ID, 3), "grant not found")
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+)
+
+// BatchResult is outcome of one batch operation.
+type BatchResult struct {
+	Op        string
+	Event     *model.Event
+	Conflicts []*model.Event
+	Err       error
+}
+
+// BatchError is returned by atomic batch when operation Index fails and nothing is applied.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// Error to provide failed operation position.
+func (e BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+// ApplyBatch applies create, update and delete operations in order.
+// Atomic batch applies all operations or none and fails with BatchError,
+// otherwise every operation is applied on its own and results carry per-operation errors.
+func (s *EventService) ApplyBatch(ops []model.BatchOperation, atomic bool, mode ConflictMode) ([]BatchResult, error) {
+	if len(ops) == 0 {
+		return nil, errors.ValidationError{
+			Field:   "operations",
+			Message: "at least one operation is required",
+		}
+	}
+	if len(ops) > model.MaxBatchOperations {
+		return nil, errors.ValidationError{
+			Field:   "operations",
+			Message: fmt.Sprintf("at most %d operations are allowed", model.MaxBatchOperations),
+		}
+	}
+
+	if !atomic {
+		results := make([]BatchResult, len(ops))
+		for i, op := range ops {
+			results[i] = s.applyOperation(op, mode)
+		}
+		return results, nil
+	}
+
+	var results []BatchResult
+	err := s.atomically(func(tx *EventService) error {
+		results = make([]BatchResult, len(ops))
+		for i, op := range ops {
+			results[i] = tx.applyOperation(op, mode)
+			if results[i].Err != nil {
+				return BatchError{Index: i, Err: results[i].Err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(BatchError); ok {
+			return results, err
+		}
+		return nil, errors.InternalError{
+			Operation: "batch",
+			Message:   err.Error(),
+		}
+	}
+	return results, nil
+}
+
+// applyOperation applies single batch operation through regular service methods.
+func (s *EventService) applyOperation(op model.BatchOperation, mode ConflictMode) BatchResult {
+	result := BatchResult{Op: op.Op}
+
+	switch op.Op {
+	case model.BatchCreate, model.BatchUpdate:
+		if op.Event == nil {
+			result.Err = errors.ValidationError{
+				Field:   "event",
+				Message: "event is required",
+			}
+			return result
+		}
+		event := *op.Event
+		if op.Op == model.BatchCreate {
+			event.ID = 0
+			result.Conflicts, result.Err = s.CreateEventChecked(&event, mode)
+		} else {
+			if op.ID != 0 {
+				event.ID = op.ID
+			}
+			result.Conflicts, result.Err = s.UpdateEventChecked(&event, mode)
+		}
+		if result.Err == nil {
+			result.Event = &event
+		}
+	case model.BatchDelete:
+		result.Err = s.DeleteEvent(op.ID)
+	default:
+		result.Err = errors.ValidationError{
+			Field:   "op",
+			Message: "op must be one of create, update, delete",
+		}
+	}
+	return result
+}
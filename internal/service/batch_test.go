@@ -0,0 +1,88 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_ApplyBatch_Atomic(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	notifier := &recordingNotifier{}
+	service := NewEventService(repo, WithNotifier(notifier))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	existing := &model.Event{UserID: 1, Date: date, Text: "Existing"}
+	require.NoError(t, service.CreateEvent(existing))
+	notifier.sent = nil
+
+	ops := []model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "New"}},
+		{Op: model.BatchDelete, ID: existing.ID},
+		{Op: model.BatchUpdate, ID: 42, Event: &model.Event{UserID: 1, Date: date, Text: "Missing"}},
+	}
+	_, err := service.ApplyBatch(ops, true, ConflictAllow)
+	require.Error(t, err)
+	batchErr, ok := err.(BatchError)
+	require.True(t, ok)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.Empty(t, notifier.sent, "nothing is announced for rolled back batch")
+
+	stored, err := service.GetEvent(existing.ID)
+	require.NoError(t, err, "delete is rolled back")
+	assert.Equal(t, "Existing", stored.Text)
+	_, err = service.GetEvent(existing.ID + 1)
+	assert.Error(t, err, "create is rolled back")
+
+	ops[2] = model.BatchOperation{Op: model.BatchUpdate, Event: &model.Event{ID: existing.ID + 1, UserID: 1, Date: date, Text: "Renamed"}}
+	results, err := service.ApplyBatch(ops, true, ConflictAllow)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "Renamed", results[2].Event.Text)
+	assert.Len(t, notifier.sent, 3)
+
+	_, err = service.GetEvent(existing.ID)
+	assert.Error(t, err)
+}
+
+func TestEventService_ApplyBatch_BestEffort(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo)
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	results, err := service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "First"}},
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date}},
+		{Op: model.BatchDelete, ID: 42},
+		{Op: "move"},
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "Second"}},
+	}, false, ConflictAllow)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.NoError(t, results[0].Err)
+	assert.IsType(t, errors.ValidationError{}, results[1].Err)
+	assert.IsType(t, errors.BusinessError{}, results[2].Err)
+	assert.IsType(t, errors.ValidationError{}, results[3].Err)
+	assert.NoError(t, results[4].Err)
+
+	events, err := service.GetEventsDay(date)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestEventService_ApplyBatch_Limits(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository())
+
+	_, err := service.ApplyBatch(nil, true, ConflictAllow)
+	assert.IsType(t, errors.ValidationError{}, err)
+
+	ops := make([]model.BatchOperation, model.MaxBatchOperations+1)
+	_, err = service.ApplyBatch(ops, true, ConflictAllow)
+	assert.IsType(t, errors.ValidationError{}, err)
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// endOfTime bounds queries that need every stored event.
+var endOfTime = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// WithCalendars makes EventService keep events in users' calendars.
+func WithCalendars(calendars repository.CalendarRepository) Option {
+	return func(s *EventService) {
+		s.calendars = calendars
+	}
+}
+
+// InCalendars returns EventService whose reads only return events of provided calendars.
+func (s *EventService) InCalendars(calendarIDs []int) *EventService {
+	selected := *s
+	selected.calendarIDs = calendarIDs
+	return &selected
+}
+
+// ParseCalendarIDs parses repeated or comma-separated calendar ids.
+func ParseCalendarIDs(values []string) ([]int, error) {
+	var ids []int
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.Atoi(part)
+			if err != nil || id <= 0 {
+				return nil, errors.ValidationError{
+					Field:   "calendar",
+					Message: "calendar must be a positive integer",
+				}
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetCalendars gets user's own calendars followed by calendars shared with the user, each with user's role.
+// The default calendar is created if user has none yet.
+func (s *EventService) GetCalendars(userID int) ([]*model.Calendar, error) {
+	if err := s.checkCalendars(userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.calendars.DefaultCalendar(userID); err != nil {
+		return nil, calendarError("get_calendars", err)
+	}
+
+	calendars, err := s.calendars.GetCalendars(userID)
+	if err != nil {
+		return nil, calendarError("get_calendars", err)
+	}
+	for _, calendar := range calendars {
+		calendar.Role = model.RoleOwner
+	}
+	if s.grants == nil {
+		return calendars, nil
+	}
+
+	grants, err := s.grants.GetUserGrants(userID)
+	if err != nil {
+		return nil, calendarError("get_calendars", err)
+	}
+	for _, grant := range grants {
+		calendar, err := s.calendars.GetCalendar(grant.CalendarID)
+		if err != nil {
+			continue
+		}
+		calendar.Role = grant.Role
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+// GetCalendar gets calendar by id if user has any role in it, other calendars look missing.
+func (s *EventService) GetCalendar(userID, id int) (*model.Calendar, error) {
+	return s.authorize("get_calendar", userID, id, model.PermissionFreeBusy)
+}
+
+// CreateCalendar creates user's calendar.
+func (s *EventService) CreateCalendar(calendar *model.Calendar) error {
+	if err := s.checkCalendars(calendar.OwnerID); err != nil {
+		return err
+	}
+	if err := validateCalendar(calendar); err != nil {
+		return err
+	}
+	if _, err := s.calendars.DefaultCalendar(calendar.OwnerID); err != nil {
+		return calendarError("create_calendar", err)
+	}
+
+	calendar.Default = false
+	calendar.Role = ""
+	if err := s.calendars.CreateCalendar(calendar); err != nil {
+		return calendarError("create_calendar", err)
+	}
+	calendar.Role = model.RoleOwner
+	return nil
+}
+
+// UpdateCalendar changes name, color and time zone of calendar, only its owner may do it.
+func (s *EventService) UpdateCalendar(userID int, calendar *model.Calendar) error {
+	stored, err := s.authorize("update_calendar", userID, calendar.ID, model.PermissionManage)
+	if err != nil {
+		return err
+	}
+	if err := validateCalendar(calendar); err != nil {
+		return err
+	}
+
+	calendar.OwnerID = stored.OwnerID
+	calendar.Default = stored.Default
+	calendar.Role = ""
+	if err := s.calendars.UpdateCalendar(calendar); err != nil {
+		return calendarError("update_calendar", err)
+	}
+	calendar.Role = stored.Role
+	return nil
+}
+
+// DeleteCalendar deletes calendar, which must not be the default one or hold events, with its grants and feeds.
+// Only calendar owner may do it.
+func (s *EventService) DeleteCalendar(userID, id int) error {
+	calendar, err := s.authorize("delete_calendar", userID, id, model.PermissionManage)
+	if err != nil {
+		return err
+	}
+	if calendar.Default {
+		return errors.ValidationError{
+			Field:   "id",
+			Message: "default calendar cannot be deleted",
+		}
+	}
+
+	// Event writes check their calendar in a transaction too, so none can land in the calendar being deleted.
+	err = s.atomically(func(tx *EventService) error {
+		count, err := tx.countCalendarEvents(id)
+		if err != nil {
+			return calendarError("delete_calendar", err)
+		}
+		if count > 0 {
+			return errors.ConflictError{
+				Operation: "delete_calendar",
+				Message:   fmt.Sprintf("calendar holds %d events, move or delete them first", count),
+			}
+		}
+		if err := tx.calendars.DeleteCalendar(id); err != nil {
+			return calendarError("delete_calendar", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if s.grants != nil {
+		if err := s.grants.DeleteCalendarGrants(id); err != nil {
+			return calendarError("delete_calendar", err)
+		}
+	}
+	if s.feeds != nil {
+		if err := s.feeds.DeleteCalendarFeeds(id); err != nil {
+			return calendarError("delete_calendar", err)
+		}
+	}
+	return nil
+}
+
+// GetCalendarFreeBusy returns merged busy intervals of calendar within [start, end) without event details.
+// Any role in the calendar allows it.
+func (s *EventService) GetCalendarFreeBusy(userID, calendarID int, start, end time.Time) (*model.FreeBusy, error) {
+	if err := validateRange(start, end); err != nil {
+		return nil, err
+	}
+	calendar, err := s.authorize("get_free_busy", userID, calendarID, model.PermissionFreeBusy)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.GetUserEventsRange(calendar.OwnerID, start.Add(-untimedBusy), end)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "get_free_busy",
+			Message:   err.Error(),
+		}
+	}
+	var calendarEvents []*model.Event
+	for _, event := range events {
+		if event.CalendarID == calendarID {
+			calendarEvents = append(calendarEvents, event)
+		}
+	}
+	return &model.FreeBusy{
+		UserID:     calendar.OwnerID,
+		CalendarID: calendarID,
+		Busy:       mergeIntervals(calendarEvents, start, end),
+	}, nil
+}
+
+// MigrateCalendars moves events that have no calendar, active and archived, to their owners'
+// default calendars and returns number of moved events.
+func (s *EventService) MigrateCalendars() (int, error) {
+	if s.calendars == nil {
+		return 0, nil
+	}
+
+	migrated := 0
+	for _, repo := range []repository.Repository{s.repo, s.archive} {
+		if repo == nil {
+			continue
+		}
+		err := repo.Transaction(func(tx repository.Repository) error {
+			events, err := tx.GetEventsRange(time.Time{}, endOfTime)
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				if event.CalendarID != 0 {
+					continue
+				}
+				calendar, err := s.calendars.DefaultCalendar(event.UserID)
+				if err != nil {
+					return err
+				}
+				moved := *event
+				moved.CalendarID = calendar.ID
+				if err := tx.UpdateEvent(moved.ID, &moved); err != nil {
+					return err
+				}
+				migrated++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, errors.InternalError{
+				Operation: "migrate_calendars",
+				Message:   err.Error(),
+			}
+		}
+	}
+	return migrated, nil
+}
+
+// assignCalendar puts event into a calendar: the one requested, the one it was in before
+// or the default one of its user. Actor must be allowed to write to the calendar,
+// and the event then belongs to calendar owner. Events of a service without calendars are left as they are.
+func (s *EventService) assignCalendar(operation string, event, before *model.Event) error {
+	if s.calendars == nil {
+		return nil
+	}
+	if event.CalendarID == 0 && before != nil {
+		event.CalendarID = before.CalendarID
+	}
+	if event.CalendarID == 0 {
+		calendar, err := s.calendars.DefaultCalendar(event.UserID)
+		if err != nil {
+			return calendarError(operation, err)
+		}
+		event.CalendarID = calendar.ID
+	}
+
+	writer, err := s.writer(operation)
+	if err != nil {
+		return err
+	}
+	calendar, err := s.authorize(operation, writer, event.CalendarID, model.PermissionWrite)
+	if e, ok := err.(errors.BusinessError); ok && e.Message == "calendar not found" {
+		return errors.ValidationError{
+			Field:   "calendar_id",
+			Message: fmt.Sprintf("calendar %d is not found", event.CalendarID),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	event.UserID = calendar.OwnerID
+	return nil
+}
+
+// countCalendarEvents counts events in calendar, archived ones included.
+func (s *EventService) countCalendarEvents(calendarID int) (int, error) {
+	count, err := s.repo.CountCalendarEvents(calendarID)
+	if err != nil || s.archive == nil {
+		return count, err
+	}
+	archived, err := s.archive.CountCalendarEvents(calendarID)
+	if err != nil {
+		return 0, err
+	}
+	return count + archived, nil
+}
+
+// checkCalendarExists fails when event calendar was deleted after it was assigned.
+func (s *EventService) checkCalendarExists(operation string, event *model.Event) error {
+	if s.calendars == nil {
+		return nil
+	}
+	_, err := s.calendars.GetCalendar(event.CalendarID)
+	if err == nil {
+		return nil
+	}
+	if err.Error() != "calendar not found" {
+		return calendarError(operation, err)
+	}
+	return errors.ValidationError{
+		Field:   "calendar_id",
+		Message: fmt.Sprintf("calendar %d is not found", event.CalendarID),
+	}
+}
+
+// rehome moves restored event whose calendar was deleted meanwhile to owner's default calendar.
+func (s *EventService) rehome(event *model.Event) error {
+	if s.calendars == nil {
+		return nil
+	}
+	if calendar, err := s.calendars.GetCalendar(event.CalendarID); err == nil && calendar.OwnerID == event.UserID {
+		return nil
+	}
+
+	calendar, err := s.calendars.DefaultCalendar(event.UserID)
+	if err != nil {
+		return calendarError("restore_event", err)
+	}
+	event.CalendarID = calendar.ID
+	if err := s.repo.UpdateEvent(event.ID, event); err != nil {
+		return repositoryError("restore_event", err)
+	}
+	return nil
+}
+
+// checkCalendars checks that calendars are configured and owner is set.
+func (s *EventService) checkCalendars(ownerID int) error {
+	if ownerID == 0 {
+		return errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if s.calendars == nil {
+		return errors.InternalError{
+			Operation: "calendars",
+			Message:   "calendars are not configured",
+		}
+	}
+	return nil
+}
+
+// validateCalendar checks calendar's name, color and time zone.
+func validateCalendar(calendar *model.Calendar) error {
+	calendar.Name = strings.TrimSpace(calendar.Name)
+	if calendar.Name == "" || len([]rune(calendar.Name)) > model.MaxCalendarNameLength {
+		return errors.ValidationError{
+			Field:   "name",
+			Message: fmt.Sprintf("name must be 1 to %d characters long", model.MaxCalendarNameLength),
+		}
+	}
+
+	color, err := normalizeColor(calendar.Color)
+	if err != nil {
+		return err
+	}
+	calendar.Color = color
+
+	if calendar.TimeZone != "" {
+		if _, err := time.LoadLocation(calendar.TimeZone); err != nil {
+			return errors.ValidationError{
+				Field:   "time_zone",
+				Message: "unknown time zone " + calendar.TimeZone,
+			}
+		}
+	}
+	return nil
+}
+
+// calendarError converts calendar repository error into business or internal error.
+func calendarError(operation string, err error) error {
+	if err.Error() == "calendar not found" {
+		return errors.BusinessError{
+			Operation: operation,
+			Message:   "calendar not found",
+		}
+	}
+	return errors.InternalError{
+		Operation: operation,
+		Message:   err.Error(),
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Calendars(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithCalendars(repository.NewMemoryCalendarRepository())).As(model.Actor{UserID: 1})
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	event := &model.Event{UserID: 1, Date: date, Text: "Dentist"}
+	require.NoError(t, service.CreateEvent(event))
+
+	calendars, err := service.GetCalendars(1)
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	assert.True(t, calendars[0].Default)
+	assert.Equal(t, calendars[0].ID, event.CalendarID, "event without calendar lands in default one")
+
+	work := &model.Calendar{OwnerID: 1, Name: " Work ", Color: "#ABCDEF", TimeZone: "Europe/Moscow", Default: true}
+	require.NoError(t, service.CreateCalendar(work))
+	assert.Equal(t, "Work", work.Name)
+	assert.False(t, work.Default, "only the first calendar is default")
+
+	assert.Error(t, service.CreateCalendar(&model.Calendar{OwnerID: 1, Name: "Bad", TimeZone: "Mars/Base"}))
+	assert.Error(t, service.CreateCalendar(&model.Calendar{OwnerID: 1}))
+
+	standup := &model.Event{UserID: 1, CalendarID: work.ID, Date: date, Text: "Standup"}
+	require.NoError(t, service.CreateEvent(standup))
+	assert.Error(t, service.As(model.Actor{UserID: 2}).CreateEvent(&model.Event{UserID: 2, CalendarID: work.ID, Date: date, Text: "Foreign"}))
+
+	standup.CalendarID = 0
+	standup.Text = "Standup moved"
+	require.NoError(t, service.UpdateEvent(standup))
+	assert.Equal(t, work.ID, standup.CalendarID, "update keeps calendar")
+
+	events, err := service.InCalendars([]int{work.ID}).GetUserEvents(1, date, date.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Standup moved", events[0].Text)
+
+	_, err = service.GetCalendar(2, work.ID)
+	assert.ErrorContains(t, err, "calendar not found")
+
+	assert.ErrorContains(t, service.DeleteCalendar(1, work.ID), "calendar holds 1 events")
+	assert.ErrorContains(t, service.DeleteCalendar(1, calendars[0].ID), "default calendar")
+
+	require.NoError(t, service.DeleteEvent(standup.ID))
+	require.NoError(t, service.DeleteCalendar(1, work.ID))
+	restored, err := service.RestoreEvent(1, standup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, calendars[0].ID, restored.CalendarID, "calendar was deleted while event was in trash")
+}
+
+func TestEventService_DeleteCalendar_Archived(t *testing.T) {
+	archive := repository.NewMemoryRepository()
+	service := NewEventService(repository.NewMemoryRepository(), WithArchive(archive),
+		WithCalendars(repository.NewMemoryCalendarRepository())).As(model.Actor{UserID: 1})
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	work := &model.Calendar{OwnerID: 1, Name: "Work"}
+	require.NoError(t, service.CreateCalendar(work))
+	require.NoError(t, archive.SaveEvent(&model.Event{ID: 10, UserID: 1, CalendarID: work.ID, Date: date, Text: "Old"}))
+
+	assert.ErrorContains(t, service.DeleteCalendar(1, work.ID), "calendar holds 1 events")
+	_, err := service.GetCalendar(1, work.ID)
+	assert.NoError(t, err)
+}
+
+// interruptedCalendarRepository runs interrupt once, right after the first calendar lookup.
+type interruptedCalendarRepository struct {
+	*repository.MemoryCalendarRepository
+	done      *bool
+	interrupt func()
+}
+
+func (r interruptedCalendarRepository) GetCalendar(id int) (*model.Calendar, error) {
+	calendar, err := r.MemoryCalendarRepository.GetCalendar(id)
+	if !*r.done {
+		*r.done = true
+		r.interrupt()
+	}
+	return calendar, err
+}
+
+func TestEventService_DeleteCalendar_DuringCreate(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	calendars := repository.NewMemoryCalendarRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	work := &model.Calendar{OwnerID: 1, Name: "Work"}
+	require.NoError(t, calendars.CreateCalendar(work))
+
+	var service *EventService
+	interrupted := interruptedCalendarRepository{calendars, new(bool), func() {
+		assert.NoError(t, service.DeleteCalendar(1, work.ID))
+	}}
+	service = NewEventService(repo, WithCalendars(interrupted)).As(model.Actor{UserID: 1})
+
+	err := service.CreateEvent(&model.Event{UserID: 1, CalendarID: work.ID, Date: date, Text: "Standup"})
+	assert.ErrorContains(t, err, "is not found")
+	count, err := repo.CountCalendarEvents(work.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count, "event is not left in deleted calendar")
+}
+
+func TestEventService_MigrateCalendars(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	archive := repository.NewMemoryRepository()
+	calendars := repository.NewMemoryCalendarRepository()
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 1, Date: date, Text: "Dentist"}))
+	require.NoError(t, repo.CreateEvent(&model.Event{UserID: 2, Date: date, Text: "Gym"}))
+	require.NoError(t, archive.SaveEvent(&model.Event{ID: 10, UserID: 1, Date: date.AddDate(-1, 0, 0), Text: "Old"}))
+
+	service := NewEventService(repo, WithArchive(archive), WithCalendars(calendars))
+	migrated, err := service.MigrateCalendars()
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+
+	first, err := calendars.DefaultCalendar(1)
+	require.NoError(t, err)
+	second, err := calendars.DefaultCalendar(2)
+	require.NoError(t, err)
+
+	event, err := repo.GetEvent(1)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, event.CalendarID)
+	event, err = repo.GetEvent(2)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, event.CalendarID)
+	event, err = archive.GetEvent(10)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, event.CalendarID)
+
+	migrated, err = service.MigrateCalendars()
+	require.NoError(t, err)
+	assert.Zero(t, migrated, "migration is idempotent")
+}
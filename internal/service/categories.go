@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"strings"
+	"unicode"
+)
+
+// WithCategories makes EventService keep users' categories and check events refer to existing ones.
+func WithCategories(categories repository.CategoryRepository) Option {
+	return func(s *EventService) {
+		s.categories = categories
+	}
+}
+
+// Tagged returns EventService whose reads only return events passing the filter.
+func (s *EventService) Tagged(filter model.TagFilter) *EventService {
+	tagged := *s
+	tagged.tags = filter
+	return &tagged
+}
+
+// ParseTagFilter parses tag parameters, a tag prefixed with ! excludes events carrying it.
+// Values may also hold comma-separated tags.
+func ParseTagFilter(values []string) (model.TagFilter, error) {
+	var filter model.TagFilter
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			exclude := strings.HasPrefix(part, "!")
+			tag, err := normalizeTag("tag", strings.TrimPrefix(part, "!"))
+			if err != nil {
+				return model.TagFilter{}, err
+			}
+			if exclude {
+				filter.Exclude = append(filter.Exclude, tag)
+			} else {
+				filter.Include = append(filter.Include, tag)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// GetCategories gets user's categories.
+func (s *EventService) GetCategories(userID int) ([]*model.Category, error) {
+	if err := s.checkCategories(userID); err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.GetCategories(userID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "get_categories",
+			Message:   err.Error(),
+		}
+	}
+	return categories, nil
+}
+
+// SaveCategory creates or replaces user's category.
+func (s *EventService) SaveCategory(category *model.Category) error {
+	if err := s.checkCategories(category.UserID); err != nil {
+		return err
+	}
+	name, err := normalizeTag("name", category.Name)
+	if err != nil {
+		return err
+	}
+	color, err := normalizeColor(category.Color)
+	if err != nil {
+		return err
+	}
+	category.Name = name
+	category.Color = color
+
+	if err := s.categories.SaveCategory(category); err != nil {
+		return errors.InternalError{
+			Operation: "save_category",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// DeleteCategory deletes user's category, events keep referring to it until changed.
+func (s *EventService) DeleteCategory(userID int, name string) error {
+	if err := s.checkCategories(userID); err != nil {
+		return err
+	}
+	if err := s.categories.DeleteCategory(userID, strings.ToLower(name)); err != nil {
+		return errors.BusinessError{
+			Operation: "delete_category",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// checkCategories checks that categories are configured and user is set.
+func (s *EventService) checkCategories(userID int) error {
+	if userID == 0 {
+		return errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if s.categories == nil {
+		return errors.InternalError{
+			Operation: "categories",
+			Message:   "categories are not configured",
+		}
+	}
+	return nil
+}
+
+// checkCategory checks that event's category, if set or changed, is defined by event owner.
+func (s *EventService) checkCategory(event, before *model.Event) error {
+	if s.categories == nil || event.Category == "" {
+		return nil
+	}
+	if before != nil && before.Category == event.Category && before.UserID == event.UserID {
+		return nil
+	}
+	if _, err := s.categories.GetCategory(event.UserID, event.Category); err != nil {
+		return errors.ValidationError{
+			Field:   "category",
+			Message: fmt.Sprintf("category %s is not defined", event.Category),
+		}
+	}
+	return nil
+}
+
+// validateLabels checks and normalizes event's tags, category and color.
+func validateLabels(event *model.Event) error {
+	if len(event.Tags) > model.MaxEventTags {
+		return errors.ValidationError{
+			Field:   "tags",
+			Message: fmt.Sprintf("event can have at most %d tags", model.MaxEventTags),
+		}
+	}
+
+	var tags []string
+	seen := make(map[string]bool, len(event.Tags))
+	for _, tag := range event.Tags {
+		normalized, err := normalizeTag("tags", tag)
+		if err != nil {
+			return err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			tags = append(tags, normalized)
+		}
+	}
+	event.Tags = tags
+
+	if event.Category != "" {
+		category, err := normalizeTag("category", event.Category)
+		if err != nil {
+			return err
+		}
+		event.Category = category
+	}
+
+	color, err := normalizeColor(event.Color)
+	if err != nil {
+		return err
+	}
+	event.Color = color
+	return nil
+}
+
+// normalizeTag lowercases tag and checks it only holds letters, digits, dashes and underscores.
+func normalizeTag(field, tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || len([]rune(tag)) > model.MaxTagLength {
+		return "", errors.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("tag must be 1 to %d characters long", model.MaxTagLength),
+		}
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", errors.ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("tag %q may only contain letters, digits, dashes and underscores", tag),
+			}
+		}
+	}
+	return tag, nil
+}
+
+// normalizeColor lowercases color and checks it is written as #rrggbb, empty color is allowed.
+func normalizeColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		return "", nil
+	}
+
+	valid := len(color) == 7 && color[0] == '#'
+	for _, r := range color[1:] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			valid = false
+		}
+	}
+	if !valid {
+		return "", errors.ValidationError{
+			Field:   "color",
+			Message: "color must be written as #rrggbb",
+		}
+	}
+	return color, nil
+}
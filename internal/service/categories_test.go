@@ -0,0 +1,108 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Labels(t *testing.T) {
+	categories := repository.NewMemoryCategoryRepository()
+	service := NewEventService(repository.NewMemoryRepository(), WithCategories(categories))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, service.SaveCategory(&model.Category{UserID: 1, Name: "Work", Color: "#FF0000"}))
+	saved, err := service.GetCategories(1)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Category{{UserID: 1, Name: "work", Color: "#ff0000"}}, saved)
+
+	event := &model.Event{
+		UserID:   1,
+		Date:     date,
+		Text:     "Standup",
+		Tags:     []string{" Daily", "daily", "Team"},
+		Category: "WORK",
+		Color:    "#00AA00",
+	}
+	require.NoError(t, service.CreateEvent(event))
+	assert.Equal(t, []string{"daily", "team"}, event.Tags)
+	assert.Equal(t, "work", event.Category)
+	assert.Equal(t, "#00aa00", event.Color)
+
+	invalid := []model.Event{
+		{Tags: []string{"two words"}},
+		{Tags: []string{""}},
+		{Category: "personal"},
+		{Category: "work", UserID: 2},
+		{Color: "red"},
+		{Color: "#12345g"},
+	}
+	for _, labels := range invalid {
+		candidate := labels
+		if candidate.UserID == 0 {
+			candidate.UserID = 1
+		}
+		candidate.Date = date
+		candidate.Text = "Invalid"
+		assert.Error(t, service.CreateEvent(&candidate), "%+v", labels)
+	}
+
+	require.NoError(t, service.DeleteCategory(1, "Work"))
+	event.Text = "Standup moved"
+	assert.NoError(t, service.UpdateEvent(event), "unchanged category of deleted definition is kept")
+	assert.ErrorContains(t, service.DeleteCategory(1, "work"), "category not found")
+}
+
+func TestEventService_Tagged(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	archive := repository.NewMemoryRepository()
+	service := NewEventService(repo, WithArchive(archive), WithSearch(repository.NewSearchIndex()))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, event := range []*model.Event{
+		{UserID: 1, Date: date, Text: "Standup", Tags: []string{"work"}},
+		{UserID: 1, Date: date.Add(time.Hour), Text: "Offsite", Tags: []string{"work", "personal"}},
+		{UserID: 1, Date: date.Add(2 * time.Hour), Text: "Gym", Tags: []string{"personal"}},
+		{UserID: 2, Date: date, Text: "Standup", Tags: []string{"work"}},
+		{UserID: 1, Date: date.AddDate(0, 0, 1), Text: "Standup", Tags: []string{"work"}},
+	} {
+		require.NoError(t, service.CreateEvent(event))
+	}
+	require.NoError(t, archive.SaveEvent(&model.Event{ID: 100, UserID: 1, Date: date, Text: "Old", Tags: []string{"work"}}))
+
+	filter, err := ParseTagFilter([]string{"work", "!personal"})
+	require.NoError(t, err)
+	tagged := service.Tagged(filter)
+
+	events, err := tagged.GetUserEvents(1, date, date.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Standup", events[0].Text)
+
+	events, err = tagged.IncludeArchived().GetEventsDay(date)
+	require.NoError(t, err)
+	assert.Len(t, events, 3, "both users and archived event")
+
+	events, err = service.Tagged(model.TagFilter{Exclude: []string{"work"}}).GetEventsWeek(date, date.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Gym", events[0].Text)
+
+	counts, err := tagged.GetPeriodCounts(1, date.Truncate(24*time.Hour), date.AddDate(0, 0, 2), model.BucketDay)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, []int{counts[0].Count, counts[1].Count})
+
+	results, err := tagged.Search(SearchQuery{UserID: 1, Text: "standup offsite gym"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = service.Tagged(model.TagFilter{Include: []string{"personal"}}).Search(SearchQuery{UserID: 1, Text: "offsite"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	_, err = ParseTagFilter([]string{"!"})
+	assert.Error(t, err)
+}
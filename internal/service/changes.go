@@ -0,0 +1,127 @@
+package service
+
+import (
+	"l2.18/internal/audit"
+	"l2.18/internal/model"
+	"l2.18/internal/notify"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+)
+
+// WithAudit makes EventService record every change of events in audit log.
+func WithAudit(recorder audit.Recorder) Option {
+	return func(s *EventService) {
+		s.audit = recorder
+	}
+}
+
+// change is event change waiting for transaction commit.
+type change struct {
+	action string
+	before *model.Event
+	after  *model.Event
+}
+
+// atomically runs fn with service bound to repository transaction, so other writers wait until it is done.
+// Changes made by fn are audited together before the transaction commits, if they cannot be audited none is
+// stored. Then they are added to event history, so revisions are numbered in the order changes are stored.
+// The rest of their recording happens once the transaction commits, or with the enclosing one.
+func (s *EventService) atomically(fn func(tx *EventService) error) error {
+	var pending []change
+	err := s.repo.Transaction(func(repo repository.Repository) error {
+		tx := *s
+		tx.repo = repo
+		tx.pending = &pending
+		if err := fn(&tx); err != nil {
+			return err
+		}
+		if s.pending != nil {
+			return nil
+		}
+		if err := s.recordAudit(pending...); err != nil {
+			return err
+		}
+		for _, c := range pending {
+			tx.recordRevision(c)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.pending != nil {
+		*s.pending = append(*s.pending, pending...)
+		return nil
+	}
+	for _, c := range pending {
+		s.record(c)
+	}
+	return nil
+}
+
+// changeKinds maps actions to notifications sent to event owner.
+var changeKinds = map[string]notify.Kind{
+	model.RevisionCreate:   notify.KindEventCreated,
+	model.RevisionUpdate:   notify.KindEventUpdated,
+	model.RevisionRollback: notify.KindEventUpdated,
+	model.RevisionDelete:   notify.KindEventDeleted,
+	model.RevisionRestore:  notify.KindEventRestored,
+}
+
+// changed keeps stored change of event until the transaction commits, then it is recorded in audit log,
+// history and search index and the owner is notified.
+func (s *EventService) changed(action string, before, after *model.Event) {
+	*s.pending = append(*s.pending, change{action: action, before: before, after: after})
+}
+
+// record records committed change in search index and notifies the owner.
+func (s *EventService) record(c change) {
+	event := c.after
+	if event == nil {
+		event = c.before
+	}
+	if s.search != nil {
+		if c.after != nil {
+			s.search.Index(c.after)
+		} else {
+			s.search.Remove(c.before.ID)
+		}
+	}
+	if kind, ok := changeKinds[c.action]; ok {
+		s.notifyChange(kind, event)
+	}
+}
+
+// recordAudit appends changes to audit log in one write. Actor is 0 when changes are not made on behalf of a user.
+func (s *EventService) recordAudit(changes ...change) error {
+	if s.audit == nil || len(changes) == 0 {
+		return nil
+	}
+
+	entries := make([]audit.Entry, 0, len(changes))
+	for _, c := range changes {
+		event := c.after
+		if event == nil {
+			event = c.before
+		}
+		entries = append(entries, audit.Entry{
+			Time:      s.Now(),
+			Action:    c.action,
+			EventID:   event.ID,
+			UserID:    event.UserID,
+			Actor:     s.actor.UserID,
+			IP:        s.actor.IP,
+			RequestID: s.actor.RequestID,
+			Before:    c.before,
+			After:     c.after,
+		})
+	}
+	if err := s.audit.Record(entries...); err != nil {
+		return errors.InternalError{
+			Operation: "audit",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	stderrors "errors"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAudit keeps entries in memory, it fails with err on entry number failOn or on every one if failOn is 0.
+type recordingAudit struct {
+	entries []audit.Entry
+	err     error
+	failOn  int
+}
+
+func (r *recordingAudit) Record(entries ...audit.Entry) error {
+	if r.err != nil && (r.failOn == 0 || len(r.entries)+len(entries) >= r.failOn) {
+		return r.err
+	}
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestEventService_Audit(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	recorder := &recordingAudit{}
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithAudit(recorder), WithClock(clock.NewFake(now)))
+	acting := service.As(model.Actor{UserID: 5, IP: "192.0.2.1", RequestID: "req-1"})
+
+	event := &model.Event{UserID: 1, Date: now, Text: "Dentist"}
+	require.NoError(t, acting.CreateEvent(event))
+	updated := *event
+	updated.Text = "Dentist, moved"
+	require.NoError(t, service.UpdateEvent(&updated))
+	require.NoError(t, service.DeleteEvent(event.ID))
+	require.NoError(t, service.PurgeEvent(1, event.ID))
+
+	require.Len(t, recorder.entries, 4)
+	created := recorder.entries[0]
+	assert.Equal(t, model.RevisionCreate, created.Action)
+	assert.Equal(t, 5, created.Actor)
+	assert.Equal(t, "192.0.2.1", created.IP)
+	assert.Equal(t, "req-1", created.RequestID)
+	assert.Nil(t, created.Before)
+	assert.Equal(t, now, created.Time)
+
+	changed := recorder.entries[1]
+	assert.Zero(t, changed.Actor, "unknown actor is not attributed to the owner")
+	assert.Equal(t, "Dentist", changed.Before.Text)
+	assert.Equal(t, "Dentist, moved", changed.After.Text)
+
+	assert.Equal(t, model.RevisionDelete, recorder.entries[2].Action)
+	assert.Nil(t, recorder.entries[2].After)
+	assert.Equal(t, model.RevisionPurge, recorder.entries[3].Action)
+	assert.Equal(t, event.ID, recorder.entries[3].EventID)
+}
+
+func TestEventService_Audit_AtomicBatch(t *testing.T) {
+	recorder := &recordingAudit{}
+	service := NewEventService(repository.NewMemoryRepository(), WithAudit(recorder))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	_, err := service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "New"}},
+		{Op: model.BatchDelete, ID: 42},
+	}, true, ConflictAllow)
+	require.Error(t, err)
+	assert.Empty(t, recorder.entries, "rolled back batch is not audited")
+
+	_, err = service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "New"}},
+	}, true, ConflictAllow)
+	require.NoError(t, err)
+	assert.Len(t, recorder.entries, 1)
+}
+
+func TestEventService_Audit_Failure(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	recorder := &recordingAudit{}
+	service := NewEventService(repo, WithAudit(recorder))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	event := &model.Event{UserID: 1, Date: date, Text: "Dentist"}
+	require.NoError(t, service.CreateEvent(event))
+
+	recorder.err = stderrors.New("disk full")
+	assert.Error(t, service.CreateEvent(&model.Event{UserID: 1, Date: date, Text: "Unaudited"}))
+	assert.Error(t, service.UpdateEvent(&model.Event{ID: event.ID, UserID: 1, Date: date, Text: "Unaudited"}))
+	assert.Error(t, service.DeleteEvent(event.ID))
+
+	events, err := repo.GetUserEventsRange(1, date, date.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1, "changes that cannot be audited are not stored")
+	assert.Equal(t, "Dentist", events[0].Text)
+	assert.Len(t, recorder.entries, 1)
+}
+
+func TestEventService_Audit_BatchFailure(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	recorder := &recordingAudit{err: stderrors.New("disk full"), failOn: 2}
+	service := NewEventService(repo, WithAudit(recorder))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	_, err := service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "First"}},
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "Second"}},
+	}, true, ConflictAllow)
+	require.Error(t, err)
+	assert.Empty(t, recorder.entries, "entries of a batch are logged together or not at all")
+
+	events, err := repo.GetUserEventsRange(1, date, date.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+)
+
+// ConflictMode tells how overlapping events of the same user are treated.
+type ConflictMode string
+
+// Conflict modes.
+const (
+	ConflictAllow  ConflictMode = "allow"
+	ConflictWarn   ConflictMode = "warn"
+	ConflictReject ConflictMode = "reject"
+)
+
+// ParseConflictMode parses conflict mode, empty value means allow.
+func ParseConflictMode(value string) (ConflictMode, error) {
+	switch mode := ConflictMode(value); mode {
+	case "":
+		return ConflictAllow, nil
+	case ConflictAllow, ConflictWarn, ConflictReject:
+		return mode, nil
+	default:
+		return "", errors.ValidationError{
+			Field:   "conflict",
+			Message: "conflict must be one of allow, warn, reject",
+		}
+	}
+}
+
+// CreateEventChecked creates event and returns overlapping events of the same user, or of the same calendar
+// when calendars are configured. In reject mode nothing is created when overlaps exist.
+// Overlaps are looked up in the same transaction as the event is stored.
+func (s *EventService) CreateEventChecked(event *model.Event, mode ConflictMode) ([]*model.Event, error) {
+	if mode == ConflictAllow || mode == "" {
+		return s.createEvent(event, mode)
+	}
+
+	var conflicts []*model.Event
+	err := s.atomically(func(tx *EventService) error {
+		var err error
+		conflicts, err = tx.createEvent(event, mode)
+		return err
+	})
+	return conflicts, err
+}
+
+// UpdateEventChecked updates event and returns overlapping events of the same user, or of the same calendar
+// when calendars are configured. In reject mode nothing is updated when overlaps exist.
+// Overlaps are looked up in the same transaction as the event is stored.
+func (s *EventService) UpdateEventChecked(event *model.Event, mode ConflictMode) ([]*model.Event, error) {
+	if err := validateUpdate(event); err != nil {
+		return nil, err
+	}
+	if (mode == ConflictAllow || mode == "") && s.matches == nil {
+		return s.updateEvent(event, model.RevisionUpdate, mode)
+	}
+
+	var conflicts []*model.Event
+	err := s.atomically(func(tx *EventService) error {
+		var err error
+		conflicts, err = tx.updateEvent(event, model.RevisionUpdate, mode)
+		return err
+	})
+	return conflicts, err
+}
+
+// checkConflicts finds overlapping events of event owner in event's calendar unless mode allows them silently.
+// Event must already be assigned to its calendar, whose owner it then belongs to.
+func (s *EventService) checkConflicts(operation string, event *model.Event, mode ConflictMode) ([]*model.Event, error) {
+	if mode == ConflictAllow || mode == "" {
+		return nil, nil
+	}
+
+	overlapping, err := s.repo.FindOverlapping(event.UserID, event.Date, event.End(), event.ID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: operation,
+			Message:   err.Error(),
+		}
+	}
+	var conflicts []*model.Event
+	for _, other := range overlapping {
+		if s.calendars == nil || other.CalendarID == event.CalendarID {
+			conflicts = append(conflicts, other)
+		}
+	}
+	if mode == ConflictReject && len(conflicts) > 0 {
+		return nil, errors.ConflictError{
+			Operation: operation,
+			Message:   "event overlaps existing events",
+			Details:   conflicts,
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_CreateEventChecked(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mode          ConflictMode
+		wantConflicts int
+		wantErr       bool
+	}{
+		{name: "allow", mode: ConflictAllow},
+		{name: "warn", mode: ConflictWarn, wantConflicts: 1},
+		{name: "reject", mode: ConflictReject, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			service := NewEventService(repo)
+
+			existing := &model.Event{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"}
+			require.NoError(t, service.CreateEvent(existing))
+			require.NoError(t, service.CreateEvent(&model.Event{UserID: 2, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Other user"}))
+
+			event := &model.Event{UserID: 1, Date: day.Add(9*time.Hour + 30*time.Minute), Duration: 30, Text: "Call"}
+			conflicts, err := service.CreateEventChecked(event, tt.mode)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				conflictErr, ok := err.(errors.ConflictError)
+				require.True(t, ok, "Expected ConflictError, got %T", err)
+				require.Len(t, conflictErr.Details, 1)
+				assert.Zero(t, event.ID)
+
+				events, err := repo.GetEventDay(day)
+				require.NoError(t, err)
+				assert.Len(t, events, 2)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotZero(t, event.ID)
+			require.Len(t, conflicts, tt.wantConflicts)
+			if tt.wantConflicts > 0 {
+				assert.Equal(t, existing.ID, conflicts[0].ID)
+			}
+		})
+	}
+}
+
+func TestEventService_UpdateEventChecked_IgnoresItself(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository())
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	first := &model.Event{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"}
+	second := &model.Event{UserID: 1, Date: day.Add(11 * time.Hour), Duration: 60, Text: "Lunch"}
+	require.NoError(t, service.CreateEvent(first))
+	require.NoError(t, service.CreateEvent(second))
+
+	moved := *second
+	moved.Date = day.Add(11*time.Hour + 30*time.Minute)
+	conflicts, err := service.UpdateEventChecked(&moved, ConflictReject)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	moved.Date = day.Add(9*time.Hour + 59*time.Minute)
+	_, err = service.UpdateEventChecked(&moved, ConflictReject)
+	_, ok := err.(errors.ConflictError)
+	assert.True(t, ok, "Expected ConflictError, got %T", err)
+
+	moved.Date = day.Add(10 * time.Hour)
+	_, err = service.UpdateEventChecked(&moved, ConflictReject)
+	assert.NoError(t, err, "back-to-back events do not conflict")
+}
+
+// slowRepository widens the window between a read and a write made outside a transaction.
+type slowRepository struct {
+	*repository.MemoryRepository
+}
+
+func (r slowRepository) GetEvent(id int) (*model.Event, error) {
+	event, err := r.MemoryRepository.GetEvent(id)
+	time.Sleep(10 * time.Millisecond)
+	return event, err
+}
+
+func (r slowRepository) FindOverlapping(userID int, start, end time.Time, excludeID int) ([]*model.Event, error) {
+	events, err := r.MemoryRepository.FindOverlapping(userID, start, end, excludeID)
+	time.Sleep(10 * time.Millisecond)
+	return events, err
+}
+
+func TestEventService_CreateEventChecked_Concurrent(t *testing.T) {
+	service := NewEventService(slowRepository{repository.NewMemoryRepository()})
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			event := &model.Event{UserID: 1, Date: date, Duration: 30, Text: "Standup"}
+			if _, err := service.CreateEventChecked(event, ConflictReject); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, created, "only one of concurrent overlapping events is stored")
+}
+
+func TestParseConflictMode(t *testing.T) {
+	mode, err := ParseConflictMode("")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictAllow, mode)
+
+	mode, err = ParseConflictMode("warn")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictWarn, mode)
+
+	_, err = ParseConflictMode("maybe")
+	assert.Error(t, err)
+}
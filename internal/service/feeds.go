@@ -0,0 +1,199 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"time"
+)
+
+// feedTokenBytes is amount of randomness in feed token, 256 bits.
+const feedTokenBytes = 32
+
+// WithFeeds lets calendar owners publish calendars through secret feed links.
+func WithFeeds(feeds repository.FeedRepository) Option {
+	return func(s *EventService) {
+		s.feeds = feeds
+	}
+}
+
+// GetFeeds gets feed links of calendar, only its owner may see them.
+func (s *EventService) GetFeeds(userID, calendarID int) ([]*model.Feed, error) {
+	if _, err := s.authorize("get_feeds", userID, calendarID, model.PermissionManage); err != nil {
+		return nil, err
+	}
+	if err := s.checkFeeds("get_feeds"); err != nil {
+		return nil, err
+	}
+
+	feeds, err := s.feeds.GetFeeds(calendarID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "get_feeds",
+			Message:   err.Error(),
+		}
+	}
+	return feeds, nil
+}
+
+// CreateFeed creates feed link with a new secret token for calendar owned by userID.
+// Feed without ExpiresAt works until revoked.
+func (s *EventService) CreateFeed(userID int, feed *model.Feed) error {
+	if _, err := s.authorize("create_feed", userID, feed.CalendarID, model.PermissionManage); err != nil {
+		return err
+	}
+	if err := s.checkFeeds("create_feed"); err != nil {
+		return err
+	}
+	now := s.Now()
+	if feed.ExpiresAt != nil && !feed.ExpiresAt.After(now) {
+		return errors.ValidationError{
+			Field:   "expires_at",
+			Message: "expiry must be in the future",
+		}
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return errors.InternalError{
+			Operation: "create_feed",
+			Message:   err.Error(),
+		}
+	}
+	feed.ID = 0
+	feed.Token = token
+	feed.CreatedAt = now
+	feed.Hits = 0
+	feed.LastHitAt = nil
+	if err := s.feeds.CreateFeed(feed); err != nil {
+		return errors.InternalError{
+			Operation: "create_feed",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// RotateFeed replaces token of feed link, the old link stops working and hit counter starts over.
+func (s *EventService) RotateFeed(userID, calendarID, feedID int) (*model.Feed, error) {
+	feed, err := s.ownedFeed("rotate_feed", userID, calendarID, feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "rotate_feed",
+			Message:   err.Error(),
+		}
+	}
+	feed.Token = token
+	feed.Hits = 0
+	feed.LastHitAt = nil
+	if err := s.feeds.UpdateFeed(feed); err != nil {
+		return nil, errors.InternalError{
+			Operation: "rotate_feed",
+			Message:   err.Error(),
+		}
+	}
+	return feed, nil
+}
+
+// RevokeFeed deletes feed link, its token stops working at once.
+func (s *EventService) RevokeFeed(userID, calendarID, feedID int) error {
+	if _, err := s.ownedFeed("revoke_feed", userID, calendarID, feedID); err != nil {
+		return err
+	}
+	if err := s.feeds.DeleteFeed(feedID); err != nil {
+		return errors.BusinessError{
+			Operation: "revoke_feed",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// ReadFeed gets calendar published by feed token with its events overlapping [start, end), counting the hit
+// once they are read.
+// Zero start defaults to a month ago and zero end to a year after start.
+// Unknown, revoked and expired tokens are not found.
+func (s *EventService) ReadFeed(token string, start, end time.Time) (*model.Calendar, []*model.Event, error) {
+	notFound := errors.BusinessError{
+		Operation: "read_feed",
+		Message:   "feed not found",
+	}
+	if token == "" || s.feeds == nil || s.calendars == nil {
+		return nil, nil, notFound
+	}
+	if start.IsZero() {
+		start = s.Now().AddDate(0, -1, 0)
+	}
+	if end.IsZero() {
+		end = start.AddDate(1, 0, 0)
+	}
+	if err := validateRange(start, end); err != nil {
+		return nil, nil, err
+	}
+
+	now := s.Now()
+	feed, err := s.feeds.FindFeed(token, now)
+	if err != nil {
+		return nil, nil, notFound
+	}
+	calendar, err := s.calendars.GetCalendar(feed.CalendarID)
+	if err != nil {
+		return nil, nil, notFound
+	}
+
+	owner := s.As(model.Actor{}).Tagged(model.TagFilter{}).InCalendars([]int{calendar.ID})
+	events, err := owner.GetUserEvents(calendar.OwnerID, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.feeds.HitFeed(token, now); err != nil {
+		return nil, nil, notFound
+	}
+	return calendar, events, nil
+}
+
+// ownedFeed gets feed of calendar after checking that user owns the calendar.
+func (s *EventService) ownedFeed(operation string, userID, calendarID, feedID int) (*model.Feed, error) {
+	if _, err := s.authorize(operation, userID, calendarID, model.PermissionManage); err != nil {
+		return nil, err
+	}
+	if err := s.checkFeeds(operation); err != nil {
+		return nil, err
+	}
+
+	feed, err := s.feeds.GetFeed(feedID)
+	if err != nil || feed.CalendarID != calendarID {
+		return nil, errors.BusinessError{
+			Operation: operation,
+			Message:   "feed not found",
+		}
+	}
+	return feed, nil
+}
+
+// checkFeeds checks that feeds are configured.
+func (s *EventService) checkFeeds(operation string) error {
+	if s.feeds == nil {
+		return errors.InternalError{
+			Operation: operation,
+			Message:   "feeds are not configured",
+		}
+	}
+	return nil
+}
+
+// newFeedToken returns random URL-safe token that cannot be guessed.
+func newFeedToken() (string, error) {
+	buf := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
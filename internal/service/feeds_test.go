@@ -0,0 +1,98 @@
+package service
+
+import (
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Feeds(t *testing.T) {
+	now := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithCalendars(repository.NewMemoryCalendarRepository()),
+		WithGrants(repository.NewMemoryGrantRepository()),
+		WithFeeds(repository.NewMemoryFeedRepository()),
+		WithClock(clk))
+
+	oncall := &model.Calendar{OwnerID: 1, Name: "On-call"}
+	require.NoError(t, service.CreateCalendar(oncall))
+	owner := service.As(model.Actor{UserID: 1})
+	require.NoError(t, owner.CreateEvent(&model.Event{UserID: 1, CalendarID: oncall.ID, Date: now.AddDate(0, 0, 5), Duration: 60, Text: "Alice on call"}))
+	require.NoError(t, owner.CreateEvent(&model.Event{UserID: 1, Date: now.AddDate(0, 0, 5), Text: "Private"}))
+	require.NoError(t, service.GrantAccess(1, &model.Grant{CalendarID: oncall.ID, UserID: 2, Role: model.RoleEditor}))
+
+	feed := &model.Feed{CalendarID: oncall.ID}
+	assert.ErrorContains(t, service.CreateFeed(2, feed), "forbidden", "only owner publishes")
+	past := now.Add(-time.Hour)
+	assert.Error(t, service.CreateFeed(1, &model.Feed{CalendarID: oncall.ID, ExpiresAt: &past}))
+
+	require.NoError(t, service.CreateFeed(1, feed))
+	assert.Len(t, feed.Token, 43, "256-bit token")
+	other := &model.Feed{CalendarID: oncall.ID}
+	require.NoError(t, service.CreateFeed(1, other))
+	assert.NotEqual(t, feed.Token, other.Token)
+
+	calendar, events, err := service.ReadFeed(feed.Token, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "On-call", calendar.Name)
+	require.Len(t, events, 1, "only events of published calendar")
+	assert.Equal(t, "Alice on call", events[0].Text)
+	_, _, err = service.ReadFeed(feed.Token, now, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	feeds, err := service.GetFeeds(1, oncall.ID)
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	assert.Equal(t, 2, feeds[0].Hits)
+	assert.Equal(t, now, *feeds[0].LastHitAt)
+	assert.Equal(t, 0, feeds[1].Hits)
+
+	rotated, err := service.RotateFeed(1, oncall.ID, feed.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, feed.Token, rotated.Token)
+	assert.Equal(t, 0, rotated.Hits)
+	_, _, err = service.ReadFeed(feed.Token, time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "feed not found", "old token stops working")
+	_, _, err = service.ReadFeed(rotated.Token, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+
+	require.NoError(t, service.RevokeFeed(1, oncall.ID, other.ID))
+	_, _, err = service.ReadFeed(other.Token, time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "feed not found")
+	assert.ErrorContains(t, service.RevokeFeed(1, oncall.ID, other.ID), "feed not found")
+
+	expiresAt := now.Add(time.Hour)
+	expiring := &model.Feed{CalendarID: oncall.ID, ExpiresAt: &expiresAt}
+	require.NoError(t, service.CreateFeed(1, expiring))
+	_, _, err = service.ReadFeed(expiring.Token, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	clk.Advance(time.Hour)
+	_, _, err = service.ReadFeed(expiring.Token, time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "feed not found", "expired")
+
+	_, _, err = service.ReadFeed("guess", time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "feed not found")
+}
+
+func TestEventService_ReadFeed_NotServed(t *testing.T) {
+	feeds := repository.NewMemoryFeedRepository()
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithCalendars(repository.NewMemoryCalendarRepository()),
+		WithFeeds(feeds))
+
+	feed := &model.Feed{CalendarID: 99, Token: "orphan"}
+	require.NoError(t, feeds.CreateFeed(feed))
+
+	_, _, err := service.ReadFeed(feed.Token, time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "feed not found")
+	stored, err := feeds.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.Hits, "hit is counted only when feed is served")
+	assert.Nil(t, stored.LastHitAt)
+}
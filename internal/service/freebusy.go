@@ -0,0 +1,135 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"sort"
+	"time"
+)
+
+// maxFreeBusyRange limits how long a free/busy window can be.
+const maxFreeBusyRange = 366 * 24 * time.Hour
+
+// untimedBusy is how long an event without duration keeps its owner busy.
+const untimedBusy = 30 * time.Minute
+
+// GetFreeBusy returns merged busy intervals of every user within [start, end) without event details.
+// With actor set only calendars the actor may see free/busy time of are counted.
+func (s *EventService) GetFreeBusy(userIDs []int, start, end time.Time) ([]model.FreeBusy, error) {
+	if err := validateRange(start, end); err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "at least one user ID is required",
+		}
+	}
+
+	result := make([]model.FreeBusy, 0, len(userIDs))
+	for _, userID := range userIDs {
+		busy, err := s.busyIntervals("get_free_busy", s.actor.UserID, userID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model.FreeBusy{
+			UserID: userID,
+			Busy:   busy,
+		})
+	}
+	return result, nil
+}
+
+// busyIntervals returns merged busy intervals of a user clipped to [start, end), counting only events
+// of calendars reader may see free/busy time of. Zero reader is not restricted.
+func (s *EventService) busyIntervals(operation string, reader, userID int, start, end time.Time) ([]model.Interval, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	visible, err := s.freeBusyCalendars(operation, reader, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.GetUserEventsRange(userID, start.Add(-untimedBusy), end)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: operation,
+			Message:   err.Error(),
+		}
+	}
+	if visible != nil {
+		var shared []*model.Event
+		for _, event := range events {
+			if visible[event.CalendarID] {
+				shared = append(shared, event)
+			}
+		}
+		events = shared
+	}
+	return mergeIntervals(events, start, end), nil
+}
+
+// validateRange checks that time window is not empty and not too long.
+func validateRange(start, end time.Time) error {
+	if start.IsZero() || end.IsZero() {
+		return errors.ValidationError{
+			Field:   "range",
+			Message: "start and end are required",
+		}
+	}
+	if !end.After(start) {
+		return errors.ValidationError{
+			Field:   "range",
+			Message: "end must be after start",
+		}
+	}
+	if end.Sub(start) > maxFreeBusyRange {
+		return errors.ValidationError{
+			Field:   "range",
+			Message: "range cannot be longer than 366 days",
+		}
+	}
+	return nil
+}
+
+// mergeIntervals turns events into sorted non-overlapping intervals clipped to [start, end).
+// Events without duration are busy for untimedBusy.
+func mergeIntervals(events []*model.Event, start, end time.Time) []model.Interval {
+	intervals := make([]model.Interval, 0, len(events))
+	for _, event := range events {
+		interval := model.Interval{Start: event.Date, End: event.End()}
+		if event.Duration == 0 {
+			interval.End = event.Date.Add(untimedBusy)
+		}
+		if interval.Start.Before(start) {
+			interval.Start = start
+		}
+		if interval.End.After(end) {
+			interval.End = end
+		}
+		if interval.End.After(interval.Start) {
+			intervals = append(intervals, interval)
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	merged := make([]model.Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !interval.Start.After(merged[last].End) {
+			if interval.End.After(merged[last].End) {
+				merged[last].End = interval.End
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_GetFreeBusy(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo)
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	events := []*model.Event{
+		{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"},
+		{UserID: 1, Date: day.Add(9*time.Hour + 30*time.Minute), Duration: 60, Text: "Overlapping"},
+		{UserID: 1, Date: day.Add(10*time.Hour + 30*time.Minute), Duration: 30, Text: "Adjacent"},
+		{UserID: 1, Date: day.Add(14 * time.Hour), Text: "No duration"},
+		{UserID: 1, Date: day.Add(-time.Hour), Duration: 120, Text: "Started yesterday"},
+		{UserID: 2, Date: day.Add(13 * time.Hour), Duration: 45, Text: "Lunch"},
+		{UserID: 3, Date: day.Add(-10 * time.Minute), Text: "Untimed, before the window"},
+	}
+	for _, event := range events {
+		require.NoError(t, service.CreateEvent(event))
+	}
+
+	result, err := service.GetFreeBusy([]int{1, 2, 3}, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	assert.Equal(t, []model.Interval{
+		{Start: day, End: day.Add(time.Hour)},
+		{Start: day.Add(9 * time.Hour), End: day.Add(11 * time.Hour)},
+		{Start: day.Add(14 * time.Hour), End: day.Add(14 * time.Hour).Add(untimedBusy)},
+	}, result[0].Busy, "events without duration are busy for untimedBusy")
+	assert.Equal(t, []model.Interval{
+		{Start: day.Add(13 * time.Hour), End: day.Add(13*time.Hour + 45*time.Minute)},
+	}, result[1].Busy)
+	assert.Equal(t, []model.Interval{
+		{Start: day, End: day.Add(-10 * time.Minute).Add(untimedBusy)},
+	}, result[2].Busy)
+}
+
+func TestEventService_GetFreeBusy_ValidationErrors(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository())
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		userIDs []int
+		start   time.Time
+		end     time.Time
+		want    string
+	}{
+		{name: "no users", start: day, end: day.Add(time.Hour), want: "at least one user ID is required"},
+		{name: "end before start", userIDs: []int{1}, start: day, end: day.Add(-time.Hour), want: "end must be after start"},
+		{name: "too long", userIDs: []int{1}, start: day, end: day.AddDate(2, 0, 0), want: "cannot be longer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetFreeBusy(tt.userIDs, tt.start, tt.end)
+			require.Error(t, err)
+
+			validationErr, ok := err.(errors.ValidationError)
+			require.True(t, ok, "Expected ValidationError, got %T", err)
+			assert.Contains(t, validationErr.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"strings"
+)
+
+// PatchEvent applies RFC 7396 JSON Merge Patch to stored event, validates the result and saves it.
+// It returns merged event and, unless mode allows them silently, overlapping events.
+// The event is read and saved in one transaction, so concurrent changes are not lost.
+func (s *EventService) PatchEvent(id int, patch []byte, mode ConflictMode) (*model.Event, []*model.Event, error) {
+	if id == 0 {
+		return nil, nil, errors.ValidationError{
+			Field:   "id",
+			Message: "event ID is required",
+		}
+	}
+
+	var patchDoc interface{}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, nil, errors.ValidationError{
+			Field:   "body",
+			Message: "invalid JSON format",
+		}
+	}
+	if _, ok := patchDoc.(map[string]interface{}); !ok {
+		return nil, nil, errors.ValidationError{
+			Field:   "body",
+			Message: "merge patch must be a JSON object",
+		}
+	}
+
+	var merged *model.Event
+	var conflicts []*model.Event
+	err := s.atomically(func(tx *EventService) error {
+		stored, err := tx.repo.GetEvent(id)
+		if err != nil {
+			return repositoryError("patch_event", err)
+		}
+
+		merged, err = mergeEvent(stored, patchDoc)
+		if err != nil {
+			return err
+		}
+		if merged.ID != id {
+			return errors.ValidationError{
+				Field:   "id",
+				Message: "event ID cannot be changed",
+			}
+		}
+		if err := validateUpdate(merged); err != nil {
+			return err
+		}
+
+		conflicts, err = tx.updateEvent(merged, model.RevisionUpdate, mode)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, conflicts, nil
+}
+
+// mergeEvent applies merge patch to JSON form of event and decodes the result back.
+func mergeEvent(event *model.Event, patch interface{}) (*model.Event, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "patch_event",
+			Message:   err.Error(),
+		}
+	}
+
+	var target interface{}
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, errors.InternalError{
+			Operation: "patch_event",
+			Message:   err.Error(),
+		}
+	}
+
+	data, err = json.Marshal(applyMergePatch(target, patch))
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "patch_event",
+			Message:   err.Error(),
+		}
+	}
+
+	var merged model.Event
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&merged); err != nil {
+		if name := strings.TrimPrefix(err.Error(), "json: unknown field "); name != err.Error() {
+			return nil, errors.ValidationError{
+				Field:   strings.Trim(name, `"`),
+				Message: "unknown field",
+			}
+		}
+		field := "body"
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok && typeErr.Field != "" {
+			field = typeErr.Field
+		}
+		return nil, errors.ValidationError{
+			Field:   field,
+			Message: "invalid value: " + err.Error(),
+		}
+	}
+	return &merged, nil
+}
+
+// applyMergePatch implements RFC 7396: objects are merged recursively, null removes a member,
+// any other value replaces the target.
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{})
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = applyMergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
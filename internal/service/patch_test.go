@@ -0,0 +1,112 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_PatchEvent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo)
+
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	event := &model.Event{UserID: 1, Date: date, Text: "Standup", Duration: 15, Reminders: []int{5}}
+	require.NoError(t, service.CreateEvent(event))
+
+	merged, _, err := service.PatchEvent(event.ID, []byte(`{"text": "Daily standup", "reminders": null}`), ConflictAllow)
+	require.NoError(t, err)
+	assert.Equal(t, "Daily standup", merged.Text)
+	assert.Equal(t, 1, merged.UserID)
+	assert.True(t, date.Equal(merged.Date))
+	assert.Equal(t, 15, merged.Duration)
+	assert.Nil(t, merged.Reminders)
+
+	stored, err := repo.GetEvent(event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Daily standup", stored.Text)
+	assert.Nil(t, stored.Reminders)
+}
+
+func TestEventService_PatchEvent_Errors(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository())
+
+	event := &model.Event{UserID: 1, Date: time.Now(), Text: "Standup"}
+	require.NoError(t, service.CreateEvent(event))
+
+	tests := []struct {
+		name  string
+		id    int
+		patch string
+		want  string
+	}{
+		{name: "not an object", id: event.ID, patch: `["text"]`, want: "merge patch must be a JSON object"},
+		{name: "broken json", id: event.ID, patch: `{"text":`, want: "invalid JSON format"},
+		{name: "removes date", id: event.ID, patch: `{"date": null}`, want: "event date is required"},
+		{name: "empties text", id: event.ID, patch: `{"text": ""}`, want: "event text cannot be empty"},
+		{name: "wrong type", id: event.ID, patch: `{"duration": "long"}`, want: "duration"},
+		{name: "changes id", id: event.ID, patch: `{"id": 99}`, want: "event ID cannot be changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := service.PatchEvent(tt.id, []byte(tt.patch), ConflictAllow)
+			require.Error(t, err)
+
+			validationErr, ok := err.(errors.ValidationError)
+			require.True(t, ok, "Expected ValidationError, got %T", err)
+			assert.Contains(t, validationErr.Error(), tt.want)
+		})
+	}
+
+	_, _, err := service.PatchEvent(999, []byte(`{"text": "x"}`), ConflictAllow)
+	_, ok := err.(errors.BusinessError)
+	assert.True(t, ok, "Expected BusinessError, got %T", err)
+}
+
+func TestEventService_PatchEvent_Concurrent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(slowRepository{repo})
+	event := &model.Event{UserID: 1, Date: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), Text: "Standup"}
+	require.NoError(t, service.CreateEvent(event))
+
+	var wg sync.WaitGroup
+	for _, patch := range []string{`{"text": "Daily standup"}`, `{"duration": 30}`} {
+		wg.Add(1)
+		go func(patch string) {
+			defer wg.Done()
+			_, _, err := service.PatchEvent(event.ID, []byte(patch), ConflictAllow)
+			assert.NoError(t, err)
+		}(patch)
+	}
+	wg.Wait()
+
+	stored, err := repo.GetEvent(event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Daily standup", stored.Text, "neither patch is lost")
+	assert.Equal(t, 30, stored.Duration, "neither patch is lost")
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{"d": "e", "f": "g"},
+	}
+	patch := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{"f": nil},
+		"h": []interface{}{"i"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{"d": "e"},
+		"h": []interface{}{"i"},
+	}, applyMergePatch(target, patch))
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"time"
+)
+
+// maxPeriodBuckets limits how many buckets a single aggregation may return.
+const maxPeriodBuckets = 400
+
+// PeriodBounds returns [start, end) of the year or quarter containing date, in date's time zone.
+func PeriodBounds(period string, date time.Time) (time.Time, time.Time, error) {
+	switch period {
+	case "year":
+		start := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(1, 0, 0), nil
+	case "quarter":
+		firstMonth := time.Month((int(date.Month())-1)/3*3 + 1)
+		start := time.Date(date.Year(), firstMonth, 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(0, 3, 0), nil
+	default:
+		return time.Time{}, time.Time{}, errors.ValidationError{
+			Field:   "period",
+			Message: "period must be one of year, quarter, custom",
+		}
+	}
+}
+
+// ParseBucket parses bucket name, empty value means day.
+func ParseBucket(value string) (model.Bucket, error) {
+	switch bucket := model.Bucket(value); bucket {
+	case "":
+		return model.BucketDay, nil
+	case model.BucketDay, model.BucketWeek:
+		return bucket, nil
+	default:
+		return "", errors.ValidationError{
+			Field:   "bucket",
+			Message: "bucket must be one of day, week",
+		}
+	}
+}
+
+// GetPeriodCounts returns number of user's events per bucket within [start, end), empty buckets included.
+// Buckets follow start's time zone.
+func (s *EventService) GetPeriodCounts(userID int, start, end time.Time, bucket model.Bucket) ([]model.BucketCount, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if err := validateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.countEvents(userID, start, end, bucket)
+	if err != nil {
+		return nil, readError("count_events", err)
+	}
+
+	var result []model.BucketCount
+	for bucketStart := bucket.BucketStart(start); bucketStart.Before(end); bucketStart = bucket.Next(bucketStart) {
+		if len(result) == maxPeriodBuckets {
+			return nil, errors.ValidationError{
+				Field:   "range",
+				Message: "too many buckets, use a larger bucket or a shorter range",
+			}
+		}
+		result = append(result, model.BucketCount{
+			Start: bucketStart,
+			Count: counts[bucketStart.Format("2006-01-02")],
+		})
+	}
+	return result, nil
+}
+
+// GetBucketEvents returns events of a single bucket the user may read, used to expand a heatmap cell.
+func (s *EventService) GetBucketEvents(userID int, date time.Time, bucket model.Bucket) ([]*model.Event, error) {
+	start := bucket.BucketStart(date)
+	events, err := s.startingEvents(userID, start, bucket.Next(start))
+	if err != nil {
+		return nil, readError("get_bucket_events", err)
+	}
+	return events, nil
+}
+
+// countEvents counts user's events per bucket, counting only events matching service's filters.
+func (s *EventService) countEvents(userID int, start, end time.Time, bucket model.Bucket) (map[string]int, error) {
+	if !s.filtered() {
+		return s.repo.CountEvents(userID, start, end, bucket)
+	}
+
+	events, err := s.startingEvents(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[bucket.BucketStart(event.Date.In(start.Location())).Format("2006-01-02")]++
+	}
+	return counts, nil
+}
+
+// startingEvents gets events starting in [start, end) the user may read.
+func (s *EventService) startingEvents(userID int, start, end time.Time) ([]*model.Event, error) {
+	return s.fetch(userID, func(repo repository.Repository, _ int) ([]*model.Event, error) {
+		return repo.GetEventsRange(start, end.Add(-time.Nanosecond))
+	}, func(event *model.Event) bool {
+		return !event.Date.Before(start) && event.Date.Before(end)
+	})
+}
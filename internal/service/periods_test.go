@@ -0,0 +1,71 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	date := time.Date(2025, 8, 14, 0, 0, 0, 0, time.UTC)
+
+	start, end, err := PeriodBounds("year", date)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = PeriodBounds("quarter", date)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = PeriodBounds("decade", date)
+	assert.Error(t, err)
+}
+
+func TestEventService_GetPeriodCounts(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository())
+
+	day := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, service.CreateEvent(&model.Event{UserID: 1, Date: day, Text: "A"}))
+	require.NoError(t, service.CreateEvent(&model.Event{UserID: 1, Date: day.Add(time.Hour), Text: "B"}))
+	require.NoError(t, service.CreateEvent(&model.Event{UserID: 1, Date: day.AddDate(0, 2, 0), Text: "C"}))
+
+	start, end, err := PeriodBounds("quarter", day)
+	require.NoError(t, err)
+
+	counts, err := service.GetPeriodCounts(1, start, end, model.BucketDay)
+	require.NoError(t, err)
+	require.Len(t, counts, 90)
+	assert.Equal(t, model.BucketCount{Start: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Count: 2}, counts[14])
+	assert.Equal(t, 1, counts[73].Count)
+
+	counts, err = service.GetPeriodCounts(1, start, end, model.BucketWeek)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), counts[0].Start, "week containing Jan 1st")
+	assert.Len(t, counts, 14)
+
+	events, err := service.GetBucketEvents(1, day, model.BucketWeek)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestEventService_GetPeriodCounts_TimeZone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	service := NewEventService(repository.NewMemoryRepository())
+
+	require.NoError(t, service.CreateEvent(&model.Event{UserID: 1, Date: time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC), Text: "Late"}))
+
+	start, end, err := PeriodBounds("year", time.Date(2025, 2, 1, 0, 0, 0, 0, moscow))
+	require.NoError(t, err)
+
+	counts, err := service.GetPeriodCounts(1, start, end, model.BucketDay)
+	require.NoError(t, err)
+	assert.Zero(t, counts[30].Count, "January 31st")
+	assert.Equal(t, 1, counts[31].Count, "February 1st in Moscow")
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"log"
+	"reflect"
+	"time"
+)
+
+// WithRevisions makes EventService keep history of every event change.
+func WithRevisions(revisions repository.RevisionRepository) Option {
+	return func(s *EventService) {
+		s.revisions = revisions
+	}
+}
+
+// As returns EventService recording changes as made by actor.
+// Without actor changes are attributed to the event owner.
+func (s *EventService) As(actor model.Actor) *EventService {
+	acting := *s
+	acting.actor = actor
+	return &acting
+}
+
+// GetRevisions gets history of user's event, oldest revision first.
+func (s *EventService) GetRevisions(userID, eventID int) ([]*model.Revision, error) {
+	return s.readableRevisions("get_revisions", userID, eventID)
+}
+
+// GetRevision gets single revision of user's event.
+func (s *EventService) GetRevision(userID, eventID, number int) (*model.Revision, error) {
+	if _, err := s.readableRevisions("get_revision", userID, eventID); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisions.GetRevision(eventID, number)
+	if err != nil {
+		return nil, errors.BusinessError{
+			Operation: "get_revision",
+			Message:   "revision not found",
+		}
+	}
+	return revision, nil
+}
+
+// RollbackEvent restores event to the state of provided revision, recording it as a new revision.
+// The user must be allowed to write to event's calendar.
+func (s *EventService) RollbackEvent(userID, eventID, number int) (*model.Event, error) {
+	revision, err := s.GetRevision(userID, eventID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	event := revision.Event
+	event.ID = eventID
+	if err := validateUpdate(&event); err != nil {
+		return nil, err
+	}
+	acting := s
+	if s.actor.UserID == 0 {
+		acting = s.As(model.Actor{UserID: userID})
+	}
+	if _, err := acting.updateEvent(&event, model.RevisionRollback, ConflictAllow); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// readableRevisions gets revisions of event the user may read as of its latest revision, other events look missing.
+func (s *EventService) readableRevisions(operation string, userID, eventID int) ([]*model.Revision, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if eventID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "id",
+			Message: "event ID is required",
+		}
+	}
+
+	notFound := errors.BusinessError{
+		Operation: operation,
+		Message:   "event not found",
+	}
+	if s.revisions == nil {
+		return nil, notFound
+	}
+	revisions, err := s.revisions.GetRevisions(eventID)
+	if err != nil || len(revisions) == 0 {
+		return nil, notFound
+	}
+	latest := revisions[len(revisions)-1].Event
+	if err := s.authorizeEvent(operation, userID, &latest, model.PermissionRead); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// recordRevision stores snapshot of changed event with changes since previous revision, failures are only logged.
+// Purged events get no revision.
+func (s *EventService) recordRevision(c change) {
+	if s.revisions == nil || c.action == model.RevisionPurge {
+		return
+	}
+	event := c.after
+	if event == nil {
+		event = c.before
+	}
+
+	revision := &model.Revision{
+		EventID: event.ID,
+		Action:  c.action,
+		Actor:   s.actor.UserID,
+		At:      s.Now(),
+		Event:   *event,
+	}
+	if revision.Actor == 0 {
+		revision.Actor = event.UserID
+	}
+	if previous, err := s.revisions.GetRevisions(event.ID); err == nil && len(previous) > 0 {
+		revision.Changes = diffEvents(&previous[len(previous)-1].Event, event)
+	}
+
+	if err := s.revisions.AddRevision(revision); err != nil {
+		log.Printf("Failed to record %s revision of event %d: %v", c.action, event.ID, err)
+	}
+}
+
+// diffEvents lists event fields that differ between two snapshots.
+func diffEvents(from, to *model.Event) []model.FieldChange {
+	var changes []model.FieldChange
+	add := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, model.FieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	add("user_id", from.UserID, to.UserID)
+	add("calendar_id", from.CalendarID, to.CalendarID)
+	if !from.Date.Equal(to.Date) {
+		changes = append(changes, model.FieldChange{
+			Field: "date",
+			From:  from.Date.Format(time.RFC3339),
+			To:    to.Date.Format(time.RFC3339),
+		})
+	}
+	add("text", from.Text, to.Text)
+	add("duration", from.Duration, to.Duration)
+	add("reminders", append([]int{}, from.Reminders...), append([]int{}, to.Reminders...))
+	add("tags", append([]string{}, from.Tags...), append([]string{}, to.Tags...))
+	add("category", from.Category, to.Category)
+	add("color", from.Color, to.Color)
+	return changes
+}
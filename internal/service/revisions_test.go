@@ -0,0 +1,142 @@
+package service
+
+import (
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Revisions(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	service := NewEventService(repository.NewMemoryRepository(),
+		WithRevisions(repository.NewMemoryRevisionRepository()), WithClock(clk))
+
+	event := &model.Event{UserID: 1, Date: now, Text: "Dentist"}
+	require.NoError(t, service.CreateEvent(event))
+
+	clk.Advance(time.Hour)
+	moved := *event
+	moved.Date = now.Add(24 * time.Hour)
+	moved.Text = "Dentist, moved"
+	require.NoError(t, service.As(model.Actor{UserID: 5}).UpdateEvent(&moved))
+
+	revisions, err := service.GetRevisions(1, event.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, model.RevisionCreate, revisions[0].Action)
+	assert.Equal(t, 1, revisions[0].Actor, "owner is the default actor")
+	assert.Empty(t, revisions[0].Changes)
+
+	assert.Equal(t, model.RevisionUpdate, revisions[1].Action)
+	assert.Equal(t, 5, revisions[1].Actor)
+	assert.Equal(t, now.Add(time.Hour), revisions[1].At)
+	assert.Equal(t, []model.FieldChange{
+		{Field: "date", From: "2024-01-15T12:00:00Z", To: "2024-01-16T12:00:00Z"},
+		{Field: "text", From: "Dentist", To: "Dentist, moved"},
+	}, revisions[1].Changes)
+
+	_, err = service.GetRevisions(2, event.ID)
+	assert.ErrorContains(t, err, "event not found")
+
+	rolledBack, err := service.RollbackEvent(1, event.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Dentist", rolledBack.Text)
+
+	stored, err := service.GetEvent(event.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Date.Equal(now))
+
+	revision, err := service.GetRevision(1, event.ID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, model.RevisionRollback, revision.Action)
+	assert.Len(t, revision.Changes, 2)
+
+	_, err = service.GetRevision(1, event.ID, 4)
+	assert.ErrorContains(t, err, "revision not found")
+
+	require.NoError(t, service.DeleteEvent(event.ID))
+	revisions, err = service.GetRevisions(1, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RevisionDelete, revisions[len(revisions)-1].Action)
+	_, err = service.RollbackEvent(1, event.ID, 1)
+	assert.ErrorContains(t, err, "event not found", "deleted event must be restored first")
+}
+
+func TestEventService_Revisions_AtomicBatch(t *testing.T) {
+	revisions := repository.NewMemoryRevisionRepository()
+	service := NewEventService(repository.NewMemoryRepository(), WithRevisions(revisions))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	_, err := service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "New"}},
+		{Op: model.BatchDelete, ID: 42},
+	}, true, ConflictAllow)
+	require.Error(t, err)
+	_, err = revisions.GetRevisions(1)
+	assert.Error(t, err, "rolled back batch leaves no history")
+
+	_, err = service.ApplyBatch([]model.BatchOperation{
+		{Op: model.BatchCreate, Event: &model.Event{UserID: 1, Date: date, Text: "New"}},
+		{Op: model.BatchUpdate, Event: &model.Event{ID: 1, UserID: 1, Date: date, Text: "Renamed"}},
+	}, true, ConflictAllow)
+	require.NoError(t, err)
+	history, err := revisions.GetRevisions(1)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[1].Number)
+	assert.Equal(t, "text", history[1].Changes[0].Field)
+}
+
+// slowRevisionRepository widens the window between reading event history and adding to it.
+type slowRevisionRepository struct {
+	*repository.MemoryRevisionRepository
+}
+
+func (r slowRevisionRepository) GetRevisions(eventID int) ([]*model.Revision, error) {
+	revisions, err := r.MemoryRevisionRepository.GetRevisions(eventID)
+	time.Sleep(5 * time.Millisecond)
+	return revisions, err
+}
+
+func TestEventService_Revisions_Concurrent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo,
+		WithRevisions(slowRevisionRepository{repository.NewMemoryRevisionRepository()}))
+	date := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	event := &model.Event{UserID: 1, Date: date, Text: "v0"}
+	require.NoError(t, service.CreateEvent(event))
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			update := *event
+			update.Text = "v" + strconv.Itoa(i)
+			assert.NoError(t, service.UpdateEvent(&update))
+		}(i)
+	}
+	wg.Wait()
+
+	revisions, err := service.GetRevisions(1, event.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 11)
+	for i, revision := range revisions[1:] {
+		previous := revisions[i]
+		assert.Equal(t, i+2, revision.Number)
+		require.Len(t, revision.Changes, 1)
+		assert.Equal(t, previous.Event.Text, revision.Changes[0].From, "changes follow previous revision")
+	}
+	stored, err := repo.GetEvent(event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Text, revisions[10].Event.Text, "latest revision is the stored event")
+}
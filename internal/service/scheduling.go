@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+	"sort"
+	"time"
+)
+
+// Defaults of meeting slot search.
+const (
+	defaultWorkStart = "09:00"
+	defaultWorkEnd   = "18:00"
+	defaultSlotStep  = 15
+	defaultSlotCount = 5
+	maxSlotCount     = 50
+)
+
+// SchedulingService suggests meeting slots based on attendees' busy time.
+type SchedulingService struct {
+	events *EventService
+}
+
+// NewSchedulingService creates new SchedulingService reading busy time of attendees from events.
+func NewSchedulingService(events *EventService) *SchedulingService {
+	return &SchedulingService{
+		events: events,
+	}
+}
+
+// FindSlots returns the earliest slots within working hours where every required attendee is free.
+// Working hours are taken in request's time zone. Requester must be allowed to see free/busy time
+// of every attendee, zero requester is not restricted.
+func (s *SchedulingService) FindSlots(requesterID int, req model.SlotRequest) ([]model.Slot, error) {
+	if err := validateSlotRequest(&req); err != nil {
+		return nil, err
+	}
+	workStart, err := parseClock("work_start", req.WorkStart)
+	if err != nil {
+		return nil, err
+	}
+	workEnd, err := parseClock("work_end", req.WorkEnd)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := loadLocation(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	if workEnd <= workStart {
+		return nil, errors.ValidationError{
+			Field:   "work_end",
+			Message: "working hours must end after they start",
+		}
+	}
+
+	duration := time.Duration(req.Duration) * time.Minute
+	gap := time.Duration(req.MinGap) * time.Minute
+	step := time.Duration(req.Step) * time.Minute
+
+	optional := make(map[int]bool, len(req.Optional))
+	for _, userID := range req.Optional {
+		optional[userID] = true
+	}
+
+	busy := make(map[int][]model.Interval)
+	for _, userID := range append(append([]int(nil), req.Attendees...), req.Optional...) {
+		if _, done := busy[userID]; done {
+			continue
+		}
+		intervals, err := s.events.busyIntervals("find_slots", requesterID, userID, req.Start.Add(-gap), req.End.Add(gap))
+		if err != nil {
+			return nil, err
+		}
+		busy[userID] = intervals
+	}
+
+	var slots []model.Slot
+	for start := alignUp(req.Start.In(loc), step); !start.Add(duration).After(req.End); start = start.Add(step) {
+		end := start.Add(duration)
+		if !withinWorkingHours(start, end, workStart, workEnd) {
+			continue
+		}
+
+		free := true
+		var busyOptional []int
+		for userID, intervals := range busy {
+			if !overlapsAny(intervals, start.Add(-gap), end.Add(gap)) {
+				continue
+			}
+			if !optional[userID] {
+				free = false
+				break
+			}
+			busyOptional = append(busyOptional, userID)
+		}
+		if !free {
+			continue
+		}
+
+		sort.Ints(busyOptional)
+		slots = append(slots, model.Slot{Start: start, End: end, BusyOptional: busyOptional})
+		if len(slots) == req.Count {
+			break
+		}
+	}
+	return slots, nil
+}
+
+// validateSlotRequest checks slot request and fills in defaults.
+func validateSlotRequest(req *model.SlotRequest) error {
+	if len(req.Attendees) == 0 {
+		return errors.ValidationError{
+			Field:   "attendees",
+			Message: "at least one attendee is required",
+		}
+	}
+	if req.Duration <= 0 || req.Duration > 24*60 {
+		return errors.ValidationError{
+			Field:   "duration",
+			Message: "duration must be between 1 and 1440 minutes",
+		}
+	}
+	if err := validateRange(req.Start, req.End); err != nil {
+		return err
+	}
+	if req.MinGap < 0 {
+		return errors.ValidationError{
+			Field:   "min_gap",
+			Message: "min_gap cannot be negative",
+		}
+	}
+	if req.Step < 0 {
+		return errors.ValidationError{
+			Field:   "step",
+			Message: "step cannot be negative",
+		}
+	}
+	if req.Count < 0 || req.Count > maxSlotCount {
+		return errors.ValidationError{
+			Field:   "count",
+			Message: fmt.Sprintf("count must be between 1 and %d", maxSlotCount),
+		}
+	}
+
+	if req.WorkStart == "" {
+		req.WorkStart = defaultWorkStart
+	}
+	if req.WorkEnd == "" {
+		req.WorkEnd = defaultWorkEnd
+	}
+	if req.Step == 0 {
+		req.Step = defaultSlotStep
+	}
+	if req.Count == 0 {
+		req.Count = defaultSlotCount
+	}
+	return nil
+}
+
+// loadLocation loads IANA time zone, empty name means UTC.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.ValidationError{
+			Field:   "time_zone",
+			Message: "unknown time zone " + name,
+		}
+	}
+	return loc, nil
+}
+
+// parseClock parses "HH:MM" into offset from midnight.
+func parseClock(field, value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.ValidationError{
+			Field:   field,
+			Message: "invalid time format. Use HH:MM",
+		}
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// alignUp rounds t up to the next multiple of step counted from local midnight.
+func alignUp(t time.Time, step time.Duration) time.Time {
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if rest := sinceMidnight % step; rest != 0 {
+		return t.Add(step - rest)
+	}
+	return t
+}
+
+// withinWorkingHours checks that [start, end) fits into working hours of start's local day.
+func withinWorkingHours(start, end time.Time, workStart, workEnd time.Duration) bool {
+	year, month, day := start.Date()
+	dayStart := time.Date(year, month, day, 0, int(workStart/time.Minute), 0, 0, start.Location())
+	dayEnd := time.Date(year, month, day, 0, int(workEnd/time.Minute), 0, 0, start.Location())
+	return !start.Before(dayStart) && !end.After(dayEnd)
+}
+
+// overlapsAny checks if [start, end) overlaps any of sorted intervals.
+func overlapsAny(intervals []model.Interval, start, end time.Time) bool {
+	for _, interval := range intervals {
+		if !interval.Start.Before(end) {
+			return false
+		}
+		if interval.End.After(start) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedulingFixture(t *testing.T, events ...*model.Event) *SchedulingService {
+	repo := repository.NewMemoryRepository()
+	for _, event := range events {
+		require.NoError(t, repo.CreateEvent(event))
+	}
+	return NewSchedulingService(NewEventService(repo))
+}
+
+func TestSchedulingService_FindSlots(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := newSchedulingFixture(t,
+		&model.Event{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"},
+		&model.Event{UserID: 2, Date: day.Add(10 * time.Hour), Duration: 30, Text: "1:1"},
+	)
+
+	slots, err := service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1, 2},
+		Duration:  30,
+		Start:     day,
+		End:       day.Add(24 * time.Hour),
+		Count:     3,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+	assert.Equal(t, day.Add(10*time.Hour+30*time.Minute), slots[0].Start)
+	assert.Equal(t, day.Add(10*time.Hour+45*time.Minute), slots[1].Start)
+	assert.Equal(t, day.Add(11*time.Hour), slots[2].Start)
+	assert.Equal(t, day.Add(11*time.Hour), slots[0].End)
+}
+
+func TestSchedulingService_FindSlots_EventWithoutDuration(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := newSchedulingFixture(t,
+		&model.Event{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"},
+		&model.Event{UserID: 1, Date: day.Add(10*time.Hour + 15*time.Minute), Text: "Call"},
+	)
+
+	slots, err := service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1},
+		Duration:  30,
+		Start:     day.Add(9 * time.Hour),
+		End:       day.Add(12 * time.Hour),
+		Count:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, day.Add(10*time.Hour+15*time.Minute).Add(untimedBusy), slots[0].Start)
+}
+
+func TestSchedulingService_FindSlots_MinGapAndWorkingHours(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := newSchedulingFixture(t,
+		&model.Event{UserID: 1, Date: day.Add(9 * time.Hour), Duration: 60, Text: "Standup"},
+		&model.Event{UserID: 1, Date: day.Add(16 * time.Hour), Duration: 60, Text: "Review"},
+	)
+
+	slots, err := service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1},
+		Duration:  60,
+		Start:     day.Add(15 * time.Hour),
+		End:       day.Add(48 * time.Hour),
+		WorkStart: "08:00",
+		WorkEnd:   "17:30",
+		MinGap:    15,
+		Count:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, day.Add(32*time.Hour), slots[0].Start, "next day 08:00")
+}
+
+func TestSchedulingService_FindSlots_OptionalAttendees(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := newSchedulingFixture(t,
+		&model.Event{UserID: 2, Date: day.Add(9 * time.Hour), Duration: 480, Text: "Offsite"},
+	)
+
+	slots, err := service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1},
+		Optional:  []int{2},
+		Duration:  30,
+		Start:     day,
+		End:       day.Add(24 * time.Hour),
+		Count:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, day.Add(9*time.Hour), slots[0].Start)
+	assert.Equal(t, []int{2}, slots[0].BusyOptional)
+
+	slots, err = service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1, 2},
+		Duration:  30,
+		Start:     day,
+		End:       day.Add(24 * time.Hour),
+		Count:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, day.Add(17*time.Hour), slots[0].Start)
+	assert.Empty(t, slots[0].BusyOptional)
+}
+
+func TestSchedulingService_FindSlots_ValidationErrors(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := newSchedulingFixture(t)
+
+	tests := []struct {
+		name string
+		req  model.SlotRequest
+		want string
+	}{
+		{
+			name: "no attendees",
+			req:  model.SlotRequest{Duration: 30, Start: day, End: day.Add(time.Hour)},
+			want: "at least one attendee is required",
+		},
+		{
+			name: "zero duration",
+			req:  model.SlotRequest{Attendees: []int{1}, Start: day, End: day.Add(time.Hour)},
+			want: "duration must be between",
+		},
+		{
+			name: "bad working hours",
+			req:  model.SlotRequest{Attendees: []int{1}, Duration: 30, Start: day, End: day.Add(time.Hour), WorkStart: "9am"},
+			want: "invalid time format",
+		},
+		{
+			name: "inverted working hours",
+			req:  model.SlotRequest{Attendees: []int{1}, Duration: 30, Start: day, End: day.Add(time.Hour), WorkStart: "18:00", WorkEnd: "09:00"},
+			want: "working hours must end after they start",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.FindSlots(0, tt.req)
+			require.Error(t, err)
+
+			validationErr, ok := err.(errors.ValidationError)
+			require.True(t, ok, "Expected ValidationError, got %T", err)
+			assert.Contains(t, validationErr.Error(), tt.want)
+		})
+	}
+}
+
+func TestSchedulingService_FindSlots_TimeZone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	service := newSchedulingFixture(t)
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	slots, err := service.FindSlots(0, model.SlotRequest{
+		Attendees: []int{1},
+		Duration:  30,
+		Start:     day,
+		End:       day.Add(24 * time.Hour),
+		TimeZone:  "Europe/Moscow",
+		Count:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, time.Date(2024, 1, 15, 9, 0, 0, 0, moscow), slots[0].Start)
+	assert.Equal(t, day.Add(6*time.Hour), slots[0].Start.UTC())
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"sort"
+	"time"
+)
+
+// Search result limits.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Search orders.
+const (
+	SortRelevance = "relevance"
+	SortDate      = "date"
+)
+
+// WithSearch makes EventService keep full-text index of event texts.
+func WithSearch(index *repository.SearchIndex) Option {
+	return func(s *EventService) {
+		s.search = index
+	}
+}
+
+// SearchQuery describes user's full-text search, zero Start and End mean unbounded.
+type SearchQuery struct {
+	UserID int
+	Text   string
+	Start  time.Time
+	End    time.Time
+	Sort   string
+	Limit  int
+}
+
+// Search finds events the user may read, own ones or ones of selected calendars, whose text contains
+// every query word, the last word also as a prefix.
+// Results are ordered by relevance, newer first on equal relevance, or chronologically with date sort.
+func (s *EventService) Search(query SearchQuery) ([]*model.SearchResult, error) {
+	if err := validateSearch(&query); err != nil {
+		return nil, err
+	}
+	if s.search == nil {
+		return nil, errors.InternalError{
+			Operation: "search",
+			Message:   "search index is not configured",
+		}
+	}
+
+	scopes, err := s.readScopes(query.UserID)
+	if err != nil {
+		return nil, readError("search", err)
+	}
+	var hits []repository.SearchHit
+	for _, scope := range scopes {
+		hits = append(hits, s.search.Search(repository.SearchQuery{
+			Text:   query.Text,
+			UserID: scope.ownerID,
+			Start:  query.Start,
+			End:    query.End,
+		})...)
+	}
+	if len(scopes) > 1 {
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].Score != hits[j].Score {
+				return hits[i].Score > hits[j].Score
+			}
+			if !hits[i].Date.Equal(hits[j].Date) {
+				return hits[i].Date.After(hits[j].Date)
+			}
+			return hits[i].ID > hits[j].ID
+		})
+	}
+
+	results := []*model.SearchResult{}
+	for _, hit := range hits {
+		if len(results) == query.Limit && query.Sort == SortRelevance {
+			break
+		}
+		event, err := s.repo.GetEvent(hit.ID)
+		if err != nil && s.includeArchived && s.archive != nil {
+			event, err = s.archive.GetEvent(hit.ID)
+		}
+		if err != nil || !inScopes(scopes, event) || !s.tags.Matches(event) {
+			continue
+		}
+		results = append(results, &model.SearchResult{Event: *event, Score: hit.Score})
+	}
+
+	if query.Sort == SortDate {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Date.Before(results[j].Date)
+		})
+		if len(results) > query.Limit {
+			results = results[:query.Limit]
+		}
+	}
+	return results, nil
+}
+
+// inScopes checks if event falls into any of scopes.
+func inScopes(scopes []readScope, event *model.Event) bool {
+	for _, scope := range scopes {
+		if scope.contains(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSearch checks search query and fills defaults.
+func validateSearch(query *SearchQuery) error {
+	if query.UserID == 0 {
+		return errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if len(repository.Tokenize(query.Text)) == 0 {
+		return errors.ValidationError{
+			Field:   "q",
+			Message: "query must contain at least one word",
+		}
+	}
+	if !query.Start.IsZero() && !query.End.IsZero() && !query.End.After(query.Start) {
+		return errors.ValidationError{
+			Field:   "end",
+			Message: "end must be after start",
+		}
+	}
+
+	switch query.Sort {
+	case "":
+		query.Sort = SortRelevance
+	case SortRelevance, SortDate:
+	default:
+		return errors.ValidationError{
+			Field:   "sort",
+			Message: "sort must be relevance or date",
+		}
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxSearchLimit {
+		return errors.ValidationError{
+			Field:   "limit",
+			Message: "limit must be between 1 and 100",
+		}
+	}
+	return nil
+}
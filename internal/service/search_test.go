@@ -0,0 +1,87 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resultTexts(results []*model.SearchResult) []string {
+	texts := make([]string, len(results))
+	for i, result := range results {
+		texts[i] = result.Text
+	}
+	return texts
+}
+
+func TestEventService_Search(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	archive := repository.NewMemoryRepository()
+	service := NewEventService(repo, WithArchive(archive), WithSearch(repository.NewSearchIndex()))
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	first := &model.Event{UserID: 1, Date: date, Text: "Dentist"}
+	second := &model.Event{UserID: 1, Date: date.AddDate(0, 1, 0), Text: "Dentist checkup"}
+	require.NoError(t, service.CreateEvent(first))
+	require.NoError(t, service.CreateEvent(second))
+
+	results, err := service.Search(SearchQuery{UserID: 1, Text: "dent"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dentist", "Dentist checkup"}, resultTexts(results))
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	results, err = service.Search(SearchQuery{UserID: 1, Text: "dentist", Sort: SortDate, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dentist"}, resultTexts(results))
+
+	updated := *second
+	updated.Text = "Checkup"
+	require.NoError(t, service.UpdateEvent(&updated))
+	require.NoError(t, service.DeleteEvent(first.ID))
+	results, err = service.Search(SearchQuery{UserID: 1, Text: "dentist"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = service.RestoreEvent(1, first.ID)
+	require.NoError(t, err)
+	results, err = service.Search(SearchQuery{UserID: 2, Text: "dentist"})
+	require.NoError(t, err)
+	assert.Empty(t, results, "events of other users are not found")
+
+	require.NoError(t, archive.SaveEvent(first))
+	require.NoError(t, repo.DeleteEvent(first.ID))
+	results, err = service.Search(SearchQuery{UserID: 1, Text: "dentist"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = service.IncludeArchived().Search(SearchQuery{UserID: 1, Text: "dentist"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dentist"}, resultTexts(results))
+}
+
+func TestEventService_Search_Validation(t *testing.T) {
+	service := NewEventService(repository.NewMemoryRepository(), WithSearch(repository.NewSearchIndex()))
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  string
+	}{
+		{"no user", SearchQuery{Text: "dentist"}, "user_id"},
+		{"no words", SearchQuery{UserID: 1, Text: "..."}, "q"},
+		{"bad range", SearchQuery{UserID: 1, Text: "a", Start: date, End: date}, "end"},
+		{"bad sort", SearchQuery{UserID: 1, Text: "a", Sort: "score"}, "sort"},
+		{"bad limit", SearchQuery{UserID: 1, Text: "a", Limit: MaxSearchLimit + 1}, "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Search(tt.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -1,26 +1,368 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
 	"l2.18/internal/model"
+	"l2.18/internal/notify"
 	"l2.18/internal/repository"
 	"l2.18/pkg/errors"
+	"log"
+	"sort"
 	"time"
 )
 
 // EventService struct holds repository for events.
 type EventService struct {
-	repo repository.Repository
+	repo       repository.Repository
+	archive    repository.Repository
+	users      repository.UserRepository
+	calendars  repository.CalendarRepository
+	grants     repository.GrantRepository
+	feeds      repository.FeedRepository
+	categories repository.CategoryRepository
+	notifier   notify.Notifier
+	revisions  repository.RevisionRepository
+	audit      audit.Recorder
+	search     *repository.SearchIndex
+	clock      clock.Clock
+
+	includeArchived bool
+	actor           model.Actor
+	pending         *[]change
+	tags            model.TagFilter
+	calendarIDs     []int
+	matches         func(stored *model.Event) bool
+}
+
+// Option configures optional EventService dependencies.
+type Option func(*EventService)
+
+// WithNotifier makes EventService notify users about changes of their events.
+func WithNotifier(notifier notify.Notifier) Option {
+	return func(s *EventService) {
+		s.notifier = notifier
+	}
+}
+
+// WithArchive lets EventService read archived events on request.
+func WithArchive(archive repository.Repository) Option {
+	return func(s *EventService) {
+		s.archive = archive
+	}
+}
+
+// WithUsers lets EventService use users' settings such as time zone.
+func WithUsers(users repository.UserRepository) Option {
+	return func(s *EventService) {
+		s.users = users
+	}
+}
+
+// WithClock replaces wall clock, used by tests.
+func WithClock(clk clock.Clock) Option {
+	return func(s *EventService) {
+		s.clock = clk
+	}
 }
 
 // NewEventService creates new EventService.
-func NewEventService(repo repository.Repository) *EventService {
-	return &EventService{
-		repo: repo,
+func NewEventService(repo repository.Repository, opts ...Option) *EventService {
+	s := &EventService{
+		repo:  repo,
+		clock: clock.Real{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IncludeArchived returns EventService whose reads also return archived events.
+func (s *EventService) IncludeArchived() *EventService {
+	withArchived := *s
+	withArchived.includeArchived = true
+	return &withArchived
+}
+
+// IfMatch returns copy of service writing events only while matches accepts their stored state.
+// The check is made in the same transaction as the write.
+func (s *EventService) IfMatch(matches func(stored *model.Event) bool) *EventService {
+	conditional := *s
+	conditional.matches = matches
+	return &conditional
+}
+
+// checkPrecondition fails unless stored event is accepted by IfMatch condition.
+func (s *EventService) checkPrecondition(operation string, stored *model.Event) error {
+	if s.matches == nil || s.matches(stored) {
+		return nil
+	}
+	return errors.PreconditionFailedError{
+		Operation: operation,
+		Message:   "event was modified",
+	}
+}
+
+// Now returns current time of service's clock.
+func (s *EventService) Now() time.Time {
+	return s.clock.Now()
+}
+
+// UserLocation returns user's time zone, false means none is set and UTC is used.
+func (s *EventService) UserLocation(userID int) (*time.Location, bool) {
+	if s.users == nil {
+		return time.UTC, false
+	}
+	user, err := s.users.GetUser(userID)
+	if err != nil || user.TimeZone == "" {
+		return time.UTC, false
+	}
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		return time.UTC, false
+	}
+	return loc, true
 }
 
 // CreateEvent creates event.
 func (s *EventService) CreateEvent(event *model.Event) error {
+	_, err := s.createEvent(event, ConflictAllow)
+	return err
+}
+
+// createEvent validates and stores event, checking overlaps once its calendar and owner are known.
+func (s *EventService) createEvent(event *model.Event, mode ConflictMode) ([]*model.Event, error) {
+	if err := validateEvent(event); err != nil {
+		return nil, err
+	}
+	if err := s.assignCalendar("create_event", event, nil); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(event, nil); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.checkConflicts("create_event", event, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.atomically(func(tx *EventService) error {
+		if err := tx.checkCalendarExists("create_event", event); err != nil {
+			return err
+		}
+		if err := tx.repo.CreateEvent(event); err != nil {
+			return errors.InternalError{
+				Operation: "create_event",
+				Message:   err.Error(),
+			}
+		}
+		tx.changed(model.RevisionCreate, nil, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// UpdateEvent updates event by and with provided info.
+func (s *EventService) UpdateEvent(event *model.Event) error {
+	_, err := s.UpdateEventChecked(event, ConflictAllow)
+	return err
+}
+
+// updateEvent stores validated event, checking overlaps once its calendar and owner are known,
+// and records the change as action.
+func (s *EventService) updateEvent(event *model.Event, action string, mode ConflictMode) ([]*model.Event, error) {
+	before, err := s.repo.GetEvent(event.ID)
+	if err != nil {
+		return nil, repositoryError("update_event", err)
+	}
+	if s.calendars != nil {
+		writer, err := s.writer("update_event")
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizeEvent("update_event", writer, before, model.PermissionWrite); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkPrecondition("update_event", before); err != nil {
+		return nil, err
+	}
+	if err := s.assignCalendar("update_event", event, before); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(event, before); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.checkConflicts("update_event", event, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.atomically(func(tx *EventService) error {
+		if err := tx.checkCalendarExists("update_event", event); err != nil {
+			return err
+		}
+		if err := tx.repo.UpdateEvent(event.ID, event); err != nil {
+			return repositoryError("update_event", err)
+		}
+		tx.changed(action, before, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// DeleteEvent moves event by provided id to trash.
+func (s *EventService) DeleteEvent(eventID int) error {
+	if eventID == 0 {
+		return errors.ValidationError{
+			Field:   "id",
+			Message: "event ID is required",
+		}
+	}
+	if s.matches != nil {
+		return s.atomically(func(tx *EventService) error {
+			return tx.deleteEvent(eventID)
+		})
+	}
+	return s.deleteEvent(eventID)
+}
+
+// deleteEvent moves stored event to trash.
+func (s *EventService) deleteEvent(eventID int) error {
+	event, err := s.repo.GetEvent(eventID)
+	if err != nil {
+		return repositoryError("delete_event", err)
+	}
+	if s.calendars != nil {
+		writer, err := s.writer("delete_event")
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeEvent("delete_event", writer, event, model.PermissionWrite); err != nil {
+			return err
+		}
+	}
+	if err := s.checkPrecondition("delete_event", event); err != nil {
+		return err
+	}
+
+	return s.atomically(func(tx *EventService) error {
+		if err := tx.repo.TrashEvent(eventID, tx.Now()); err != nil {
+			return repositoryError("delete_event", err)
+		}
+		tx.changed(model.RevisionDelete, event, nil)
+		return nil
+	})
+}
+
+// GetEvent gets event by id.
+func (s *EventService) GetEvent(id int) (*model.Event, error) {
+	if id == 0 {
+		return nil, errors.ValidationError{
+			Field:   "id",
+			Message: "event ID is required",
+		}
+	}
+
+	event, err := s.repo.GetEvent(id)
+	if err != nil {
+		return nil, repositoryError("get_event", err)
+	}
+	return event, nil
+}
+
+// GetUserEvent gets event by id if the user may read it, other events look missing.
+func (s *EventService) GetUserEvent(userID, id int) (*model.Event, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+
+	event, err := s.GetEvent(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeEvent("get_event", userID, event, model.PermissionRead); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetUserEvents gets events overlapping [start, end) the user may read: own ones or ones of selected calendars.
+func (s *EventService) GetUserEvents(userID int, start, end time.Time) ([]*model.Event, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if err := validateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	events, err := s.fetch(userID, func(repo repository.Repository, ownerID int) ([]*model.Event, error) {
+		return repo.GetUserEventsRange(ownerID, start, end)
+	}, func(event *model.Event) bool {
+		return event.Overlaps(start, end)
+	})
+	if err != nil {
+		return nil, readError("get_user_events", err)
+	}
+	return events, nil
+}
+
+// GetEventsDay get all events for a day, only ones actor may read if actor is set.
+func (s *EventService) GetEventsDay(date time.Time) ([]*model.Event, error) {
+	events, err := s.fetch(s.actor.UserID, func(repo repository.Repository, _ int) ([]*model.Event, error) {
+		return repo.GetEventDay(date)
+	}, func(event *model.Event) bool {
+		return isSameDay(event.Date.In(date.Location()), date)
+	})
+	if err != nil {
+		return nil, readError("get_events_day", err)
+	}
+	return events, nil
+}
+
+// GetEventsWeek get all events for a week, only ones actor may read if actor is set.
+func (s *EventService) GetEventsWeek(dayStart, dayEnd time.Time) ([]*model.Event, error) {
+	events, err := s.fetch(s.actor.UserID, func(repo repository.Repository, _ int) ([]*model.Event, error) {
+		return repo.GetEventWeek(dayStart, dayEnd)
+	}, func(event *model.Event) bool {
+		return within(event, dayStart, dayEnd)
+	})
+	if err != nil {
+		return nil, readError("get_events_week", err)
+	}
+	return events, nil
+}
+
+// GetEventsMonth get all events for a month, only ones actor may read if actor is set.
+func (s *EventService) GetEventsMonth(dayStart, dayEnd time.Time) ([]*model.Event, error) {
+	events, err := s.fetch(s.actor.UserID, func(repo repository.Repository, _ int) ([]*model.Event, error) {
+		return repo.GetEventMonth(dayStart, dayEnd)
+	}, func(event *model.Event) bool {
+		return within(event, dayStart, dayEnd)
+	})
+	if err != nil {
+		return nil, readError("get_events_month", err)
+	}
+	return events, nil
+}
+
+// validateEvent checks event before creation.
+func validateEvent(event *model.Event) error {
 	if event.Text == "" {
 		return errors.ValidationError{
 			Field:   "text",
@@ -39,19 +381,17 @@ func (s *EventService) CreateEvent(event *model.Event) error {
 			Message: "event date is required",
 		}
 	}
-
-	err := s.repo.CreateEvent(event)
-	if err != nil {
-		return errors.InternalError{
-			Operation: "create_event",
-			Message:   err.Error(),
-		}
+	if err := validateDuration(event); err != nil {
+		return err
 	}
-	return nil
+	if err := validateReminders(event); err != nil {
+		return err
+	}
+	return validateLabels(event)
 }
 
-// UpdateEvent updates event by and with provided info.
-func (s *EventService) UpdateEvent(event *model.Event) error {
+// validateUpdate checks event before update.
+func validateUpdate(event *model.Event) error {
 	if event.ID == 0 {
 		return errors.ValidationError{
 			Field:   "id",
@@ -70,80 +410,190 @@ func (s *EventService) UpdateEvent(event *model.Event) error {
 			Message: "event text cannot be empty",
 		}
 	}
+	if event.Date.IsZero() {
+		return errors.ValidationError{
+			Field:   "date",
+			Message: "event date is required",
+		}
+	}
+	if err := validateDuration(event); err != nil {
+		return err
+	}
+	if err := validateReminders(event); err != nil {
+		return err
+	}
+	return validateLabels(event)
+}
 
-	err := s.repo.UpdateEvent(event.ID, event)
-	if err != nil {
-		if err.Error() == "event not found" {
-			return errors.BusinessError{
-				Operation: "update_event",
-				Message:   "event not found",
-			}
+// validateDuration checks that event duration is within allowed bounds.
+func validateDuration(event *model.Event) error {
+	if event.Duration < 0 || event.Duration > model.MaxDurationMinutes {
+		return errors.ValidationError{
+			Field:   "duration",
+			Message: fmt.Sprintf("duration must be between 0 and %d minutes", model.MaxDurationMinutes),
 		}
-		return errors.InternalError{
-			Operation: "update_event",
-			Message:   err.Error(),
+	}
+	return nil
+}
+
+// validateReminders checks that every reminder offset is within allowed bounds.
+func validateReminders(event *model.Event) error {
+	for _, minutes := range event.Reminders {
+		if minutes <= 0 || minutes > model.MaxReminderMinutes {
+			return errors.ValidationError{
+				Field:   "reminders",
+				Message: fmt.Sprintf("reminder offset must be between 1 and %d minutes", model.MaxReminderMinutes),
+			}
 		}
 	}
 	return nil
 }
 
-// DeleteEvent deletes event by provided id.
-func (s *EventService) DeleteEvent(eventID int) error {
-	if eventID == 0 {
-		return errors.ValidationError{
-			Field:   "id",
-			Message: "event ID is required",
+// readScope is part of events a reader may see: events of one owner, limited to listed calendars if any.
+// Zero owner means events of all users.
+type readScope struct {
+	ownerID     int
+	calendarIDs []int
+}
+
+// contains checks if event falls into the scope.
+func (scope readScope) contains(event *model.Event) bool {
+	if scope.ownerID != 0 && event.UserID != scope.ownerID {
+		return false
+	}
+	if len(scope.calendarIDs) == 0 {
+		return true
+	}
+	for _, id := range scope.calendarIDs {
+		if event.CalendarID == id {
+			return true
 		}
 	}
+	return false
+}
 
-	err := s.repo.DeleteEvent(eventID)
-	if err != nil {
-		if err.Error() == "event not found" {
-			return errors.BusinessError{
-				Operation: "delete_event",
-				Message:   "event not found",
-			}
+// readScopes returns parts of events reader may see: own events or events of selected calendars,
+// each of which reader must be allowed to read. Zero reader is not restricted.
+func (s *EventService) readScopes(reader int) ([]readScope, error) {
+	if reader == 0 {
+		return []readScope{{calendarIDs: s.calendarIDs}}, nil
+	}
+	if len(s.calendarIDs) == 0 || s.calendars == nil {
+		return []readScope{{ownerID: reader, calendarIDs: s.calendarIDs}}, nil
+	}
+
+	var scopes []readScope
+	byOwner := make(map[int]int)
+	for _, id := range s.calendarIDs {
+		calendar, err := s.authorize("read_events", reader, id, model.PermissionRead)
+		if err != nil {
+			return nil, err
 		}
-		return errors.InternalError{
-			Operation: "delete_event",
-			Message:   err.Error(),
+		i, exists := byOwner[calendar.OwnerID]
+		if !exists {
+			i = len(scopes)
+			byOwner[calendar.OwnerID] = i
+			scopes = append(scopes, readScope{ownerID: calendar.OwnerID})
 		}
+		scopes[i].calendarIDs = append(scopes[i].calendarIDs, id)
 	}
-	return nil
+	return scopes, nil
 }
 
-// GetEventsDay get all events for a day.
-func (s *EventService) GetEventsDay(date time.Time) ([]*model.Event, error) {
-	events, err := s.repo.GetEventDay(date)
+// fetch runs read query for every scope of reader on active repository and, if requested, on archive as well.
+// Query gets owner of the scope, zero for all users. Events outside the scope, outside the window
+// or not passing tag filter are dropped. With included tags, events are looked up in tag index instead.
+func (s *EventService) fetch(reader int, query func(repo repository.Repository, ownerID int) ([]*model.Event, error), inWindow func(event *model.Event) bool) ([]*model.Event, error) {
+	scopes, err := s.readScopes(reader)
 	if err != nil {
-		return nil, errors.InternalError{
-			Operation: "get_events_day",
-			Message:   err.Error(),
+		return nil, err
+	}
+	repos := []repository.Repository{s.repo}
+	if s.includeArchived && s.archive != nil {
+		repos = append(repos, s.archive)
+	}
+
+	var events []*model.Event
+	for _, scope := range scopes {
+		for _, repo := range repos {
+			var found []*model.Event
+			if len(s.tags.Include) > 0 {
+				found, err = repo.GetTaggedEvents(s.tags.Include)
+			} else {
+				found, err = query(repo, scope.ownerID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range found {
+				if inWindow(event) && scope.contains(event) && s.tags.Matches(event) {
+					events = append(events, event)
+				}
+			}
 		}
 	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
 	return events, nil
 }
 
-// GetEventsWeek get all events for a week.
-func (s *EventService) GetEventsWeek(dayStart, dayEnd time.Time) ([]*model.Event, error) {
-	events, err := s.repo.GetEventWeek(dayStart, dayEnd)
-	if err != nil {
-		return nil, errors.InternalError{
-			Operation: "get_events_week",
-			Message:   err.Error(),
+// filtered checks if reads are limited by tags or calendars.
+func (s *EventService) filtered() bool {
+	return !s.tags.Empty() || len(s.calendarIDs) > 0
+}
+
+// within checks if event starts between provided dates, both inclusive.
+func within(event *model.Event, start, end time.Time) bool {
+	return !event.Date.Before(start) && !event.Date.After(end)
+}
+
+// isSameDay checks if both times fall on the same calendar day.
+func isSameDay(date1, date2 time.Time) bool {
+	y1, m1, d1 := date1.Date()
+	y2, m2, d2 := date2.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// repositoryError converts repository error into business or internal error.
+func repositoryError(operation string, err error) error {
+	if err.Error() == "event not found" {
+		return errors.BusinessError{
+			Operation: operation,
+			Message:   "event not found",
 		}
 	}
-	return events, nil
+	return errors.InternalError{
+		Operation: operation,
+		Message:   err.Error(),
+	}
 }
 
-// GetEventsMonth get all events for a month.
-func (s *EventService) GetEventsMonth(dayStart, dayEnd time.Time) ([]*model.Event, error) {
-	events, err := s.repo.GetEventMonth(dayStart, dayEnd)
-	if err != nil {
-		return nil, errors.InternalError{
-			Operation: "get_events_month",
+// readError passes errors describing the request through and converts others into internal error.
+func readError(operation string, err error) error {
+	switch err.(type) {
+	case errors.ValidationError, errors.BusinessError, errors.ForbiddenError:
+		return err
+	default:
+		return errors.InternalError{
+			Operation: operation,
 			Message:   err.Error(),
 		}
 	}
-	return events, nil
+}
+
+// notifyChange tells event owner about a change, failures are only logged.
+func (s *EventService) notifyChange(kind notify.Kind, event *model.Event) {
+	if s.notifier == nil {
+		return
+	}
+	err := s.notifier.Notify(context.Background(), notify.Notification{
+		Kind:   kind,
+		UserID: event.UserID,
+		Event:  *event,
+	})
+	if err != nil {
+		log.Printf("Failed to send %s notification for event %d: %v", kind, event.ID, err)
+	}
 }
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"l2.18/internal/model"
+	"l2.18/internal/notify"
 	"l2.18/internal/repository"
 	"l2.18/pkg/errors"
 	"testing"
@@ -50,6 +52,16 @@ func TestEventService_CreateEvent_ValidationErrors(t *testing.T) {
 			event: &model.Event{UserID: 1, Date: time.Time{}, Text: "Test"},
 			want:  "event date is required",
 		},
+		{
+			name:  "negative reminder",
+			event: &model.Event{UserID: 1, Date: time.Now(), Text: "Test", Reminders: []int{-5}},
+			want:  "reminder offset must be between",
+		},
+		{
+			name:  "too distant reminder",
+			event: &model.Event{UserID: 1, Date: time.Now(), Text: "Test", Reminders: []int{model.MaxReminderMinutes + 1}},
+			want:  "reminder offset must be between",
+		},
 	}
 
 	for _, tt := range tests {
@@ -105,6 +117,11 @@ func TestEventService_UpdateEvent_ValidationErrors(t *testing.T) {
 			event: &model.Event{ID: 1, UserID: 1, Date: time.Now(), Text: ""},
 			want:  "event text cannot be empty",
 		},
+		{
+			name:  "zero date",
+			event: &model.Event{ID: 1, UserID: 1, Text: "Test"},
+			want:  "event date is required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +195,29 @@ func TestEventService_DeleteEvent_NotFound(t *testing.T) {
 	assert.Contains(t, businessErr.Error(), "event not found")
 }
 
+func TestEventService_IfMatch(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo)
+
+	event := &model.Event{UserID: 1, Date: time.Now(), Text: "Original"}
+	require.NoError(t, service.CreateEvent(event))
+
+	unchanged := service.IfMatch(func(stored *model.Event) bool {
+		return stored.Text == "Original"
+	})
+	require.NoError(t, unchanged.UpdateEvent(&model.Event{ID: event.ID, UserID: 1, Date: event.Date, Text: "First"}))
+
+	err := unchanged.UpdateEvent(&model.Event{ID: event.ID, UserID: 1, Date: event.Date, Text: "Second"})
+	assert.IsType(t, errors.PreconditionFailedError{}, err)
+	_, _, err = unchanged.PatchEvent(event.ID, []byte(`{"text": "Third"}`), ConflictAllow)
+	assert.IsType(t, errors.PreconditionFailedError{}, err)
+	assert.IsType(t, errors.PreconditionFailedError{}, unchanged.DeleteEvent(event.ID))
+
+	stored, err := repo.GetEvent(event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "First", stored.Text)
+}
+
 func TestEventService_GetEventsDay(t *testing.T) {
 	repo := repository.NewMemoryRepository()
 	service := NewEventService(repo)
@@ -231,3 +271,88 @@ func TestEventService_GetEventsMonth(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, events, 2)
 }
+
+type recordingNotifier struct {
+	sent []notify.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notify.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestEventService_NotifiesAboutChanges(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	notifier := &recordingNotifier{}
+	service := NewEventService(repo, WithNotifier(notifier))
+
+	event := &model.Event{UserID: 3, Date: time.Now(), Text: "Planning"}
+	require.NoError(t, service.CreateEvent(event))
+
+	updated := *event
+	updated.Text = "Planning v2"
+	require.NoError(t, service.UpdateEvent(&updated))
+	require.NoError(t, service.DeleteEvent(event.ID))
+
+	require.Len(t, notifier.sent, 3)
+	assert.Equal(t, notify.KindEventCreated, notifier.sent[0].Kind)
+	assert.Equal(t, notify.KindEventUpdated, notifier.sent[1].Kind)
+	assert.Equal(t, notify.KindEventDeleted, notifier.sent[2].Kind)
+	assert.Equal(t, 3, notifier.sent[2].UserID)
+	assert.Equal(t, "Planning v2", notifier.sent[2].Event.Text)
+}
+
+func TestEventService_IncludeArchived(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	archive := repository.NewMemoryRepository()
+	service := NewEventService(repo, WithArchive(archive))
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, service.CreateEvent(&model.Event{UserID: 1, Date: date.Add(time.Hour), Text: "Active"}))
+	require.NoError(t, archive.SaveEvent(&model.Event{ID: 100, UserID: 1, Date: date, Text: "Archived"}))
+
+	events, err := service.GetEventsDay(date)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = service.IncludeArchived().GetEventsDay(date)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Archived", events[0].Text)
+	assert.Equal(t, "Active", events[1].Text)
+}
+
+func TestEventService_UserLocation(t *testing.T) {
+	users := repository.NewMemoryUserRepository()
+	require.NoError(t, users.SaveUser(&model.User{ID: 1, TimeZone: "Europe/Moscow"}))
+	service := NewEventService(repository.NewMemoryRepository(), WithUsers(users))
+
+	loc, found := service.UserLocation(1)
+	assert.True(t, found)
+	assert.Equal(t, "Europe/Moscow", loc.String())
+
+	loc, found = service.UserLocation(2)
+	assert.False(t, found)
+	assert.Equal(t, time.UTC, loc)
+}
+
+func TestEventService_GetUserEvent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewEventService(repo)
+
+	event := &model.Event{UserID: 1, Date: time.Now(), Text: "Dentist"}
+	require.NoError(t, service.CreateEvent(event))
+
+	got, err := service.GetUserEvent(1, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Dentist", got.Text)
+
+	_, err = service.GetUserEvent(2, event.ID)
+	assert.ErrorContains(t, err, "event not found")
+
+	_, err = service.GetUserEvent(1, event.ID+1)
+	assert.ErrorContains(t, err, "event not found")
+
+	_, err = service.GetUserEvent(0, event.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/pkg/errors"
+)
+
+// GetTrash gets user's deleted events that are not purged yet.
+func (s *EventService) GetTrash(userID int) ([]*model.TrashedEvent, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+
+	trashed, err := s.repo.GetTrash(userID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: "get_trash",
+			Message:   err.Error(),
+		}
+	}
+	if trashed == nil {
+		trashed = []*model.TrashedEvent{}
+	}
+	return trashed, nil
+}
+
+// RestoreEvent moves user's event from trash back to the calendar.
+func (s *EventService) RestoreEvent(userID, id int) (*model.Event, error) {
+	if _, err := s.trashedEvent("restore_event", userID, id); err != nil {
+		return nil, err
+	}
+
+	var event *model.Event
+	err := s.atomically(func(tx *EventService) error {
+		var err error
+		event, err = tx.repo.RestoreEvent(id)
+		if err != nil {
+			return repositoryError("restore_event", err)
+		}
+		if err := tx.rehome(event); err != nil {
+			return err
+		}
+		tx.changed(model.RevisionRestore, nil, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// PurgeEvent permanently removes user's event from trash.
+func (s *EventService) PurgeEvent(userID, id int) error {
+	trashed, err := s.trashedEvent("purge_event", userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.atomically(func(tx *EventService) error {
+		if err := tx.repo.PurgeEvent(id); err != nil {
+			return repositoryError("purge_event", err)
+		}
+		tx.changed(model.RevisionPurge, &trashed.Event, nil)
+		return nil
+	})
+}
+
+// trashedEvent finds user's event in trash, events of other users look missing.
+func (s *EventService) trashedEvent(operation string, userID, id int) (*model.TrashedEvent, error) {
+	if userID == 0 {
+		return nil, errors.ValidationError{
+			Field:   "user_id",
+			Message: "user ID is required",
+		}
+	}
+	if id == 0 {
+		return nil, errors.ValidationError{
+			Field:   "id",
+			Message: "event ID is required",
+		}
+	}
+
+	trashed, err := s.repo.GetTrash(userID)
+	if err != nil {
+		return nil, errors.InternalError{
+			Operation: operation,
+			Message:   err.Error(),
+		}
+	}
+	for _, item := range trashed {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, errors.BusinessError{
+		Operation: operation,
+		Message:   "event not found",
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/notify"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_TrashAndRestore(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryRepository()
+	notifier := &recordingNotifier{}
+	service := NewEventService(repo, WithNotifier(notifier), WithClock(clock.NewFake(now)))
+
+	event := &model.Event{UserID: 1, Date: now, Text: "Dentist"}
+	require.NoError(t, service.CreateEvent(event))
+	require.NoError(t, service.DeleteEvent(event.ID))
+
+	trash, err := service.GetTrash(1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, now, trash[0].DeletedAt)
+
+	trash, err = service.GetTrash(2)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+
+	_, err = service.RestoreEvent(2, event.ID)
+	assert.ErrorContains(t, err, "event not found", "trash of another user")
+	assert.ErrorContains(t, service.PurgeEvent(2, event.ID), "event not found")
+
+	restored, err := service.RestoreEvent(1, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Dentist", restored.Text)
+	assert.Equal(t, notify.KindEventRestored, notifier.sent[len(notifier.sent)-1].Kind)
+
+	require.NoError(t, service.DeleteEvent(event.ID))
+	require.NoError(t, service.PurgeEvent(1, event.ID))
+	_, err = service.RestoreEvent(1, event.ID)
+	assert.ErrorContains(t, err, "event not found")
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"l2.18/pkg/errors"
+	"net/mail"
+	"time"
+)
+
+// UserService struct holds repository for user settings.
+type UserService struct {
+	repo repository.UserRepository
+}
+
+// NewUserService creates new UserService.
+func NewUserService(repo repository.UserRepository) *UserService {
+	return &UserService{
+		repo: repo,
+	}
+}
+
+// GetUser gets user settings by id.
+func (s *UserService) GetUser(id int) (*model.User, error) {
+	if id == 0 {
+		return nil, errors.ValidationError{
+			Field:   "id",
+			Message: "user ID is required",
+		}
+	}
+
+	user, err := s.repo.GetUser(id)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.BusinessError{
+				Operation: "get_user",
+				Message:   "user not found",
+			}
+		}
+		return nil, errors.InternalError{
+			Operation: "get_user",
+			Message:   err.Error(),
+		}
+	}
+	return user, nil
+}
+
+// UpdateUser saves user's email and notification preferences.
+func (s *UserService) UpdateUser(user *model.User) error {
+	if user.ID == 0 {
+		return errors.ValidationError{
+			Field:   "id",
+			Message: "user ID is required",
+		}
+	}
+	if user.Email != "" {
+		if _, err := mail.ParseAddress(user.Email); err != nil {
+			return errors.ValidationError{
+				Field:   "email",
+				Message: "invalid email address",
+			}
+		}
+	}
+
+	if user.TimeZone != "" {
+		if _, err := time.LoadLocation(user.TimeZone); err != nil {
+			return errors.ValidationError{
+				Field:   "time_zone",
+				Message: "unknown time zone " + user.TimeZone,
+			}
+		}
+	}
+
+	if err := s.repo.SaveUser(user); err != nil {
+		return errors.InternalError{
+			Operation: "update_user",
+			Message:   err.Error(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"log"
+	"time"
+)
+
+// Purger permanently removes events that stayed in trash longer than retention.
+type Purger struct {
+	repo      repository.Repository
+	audit     audit.Recorder
+	clock     clock.Clock
+	retention time.Duration
+}
+
+// NewPurger creates new Purger, purged events are recorded in audit log unless recorder is nil.
+func NewPurger(repo repository.Repository, recorder audit.Recorder, clk clock.Clock, retention time.Duration) *Purger {
+	return &Purger{
+		repo:      repo,
+		audit:     recorder,
+		clock:     clk,
+		retention: retention,
+	}
+}
+
+// Run purges expired trash every interval until context is cancelled.
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeOnce(); err != nil {
+			log.Printf("Trash purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes events deleted more than retention ago and returns their number.
+// Nothing is removed if the purge cannot be audited.
+func (p *Purger) PurgeOnce() (int, error) {
+	now := p.clock.Now()
+	var purged []*model.TrashedEvent
+	err := p.repo.Transaction(func(tx repository.Repository) error {
+		var err error
+		purged, err = tx.PurgeTrash(now.Add(-p.retention))
+		if err != nil || p.audit == nil || len(purged) == 0 {
+			return err
+		}
+		entries := make([]audit.Entry, 0, len(purged))
+		for _, item := range purged {
+			event := item.Event
+			entries = append(entries, audit.Entry{
+				Time:    now,
+				Action:  model.RevisionPurge,
+				EventID: event.ID,
+				UserID:  event.UserID,
+				Before:  &event,
+			})
+		}
+		if err := p.audit.Record(entries...); err != nil {
+			return fmt.Errorf("audit purge of %d events: %w", len(purged), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(purged) > 0 {
+		log.Printf("Trash purger: purged %d events", len(purged))
+	}
+	return len(purged), nil
+}
@@ -0,0 +1,83 @@
+package trash
+
+import (
+	"errors"
+	"l2.18/internal/audit"
+	"l2.18/internal/clock"
+	"l2.18/internal/model"
+	"l2.18/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurger_PurgeOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	repo := repository.NewMemoryRepository()
+
+	old := &model.Event{UserID: 1, Date: now, Text: "Old"}
+	fresh := &model.Event{UserID: 1, Date: now, Text: "Fresh"}
+	require.NoError(t, repo.CreateEvent(old))
+	require.NoError(t, repo.CreateEvent(fresh))
+	require.NoError(t, repo.TrashEvent(old.ID, now.Add(-31*24*time.Hour)))
+	require.NoError(t, repo.TrashEvent(fresh.ID, now.Add(-time.Hour)))
+
+	purger := NewPurger(repo, nil, clk, 30*24*time.Hour)
+	purged, err := purger.PurgeOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	trashed, err := repo.GetTrash(1)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, "Fresh", trashed[0].Text)
+
+	clk.Advance(30 * 24 * time.Hour)
+	purged, err = purger.PurgeOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
+
+type recordingAudit struct {
+	entries []audit.Entry
+	err     error
+}
+
+func (r *recordingAudit) Record(entries ...audit.Entry) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestPurger_Audit(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryRepository()
+	event := &model.Event{UserID: 1, Date: now, Text: "Old"}
+	require.NoError(t, repo.CreateEvent(event))
+	require.NoError(t, repo.TrashEvent(event.ID, now.Add(-31*24*time.Hour)))
+
+	recorder := &recordingAudit{err: errors.New("disk full")}
+	purger := NewPurger(repo, recorder, clock.NewFake(now), 30*24*time.Hour)
+	_, err := purger.PurgeOnce()
+	assert.Error(t, err)
+	trashed, err := repo.GetTrash(1)
+	require.NoError(t, err)
+	assert.Len(t, trashed, 1, "purge that cannot be audited is not made")
+
+	recorder.err = nil
+	purged, err := purger.PurgeOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	require.Len(t, recorder.entries, 1)
+	entry := recorder.entries[0]
+	assert.Equal(t, model.RevisionPurge, entry.Action)
+	assert.Equal(t, event.ID, entry.EventID)
+	assert.Equal(t, 1, entry.UserID)
+	assert.Zero(t, entry.Actor)
+	assert.Equal(t, "Old", entry.Before.Text)
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"l2.18/internal/idempotency"
+	"net/http"
+)
+
+// IdempotencyKeyHeader is header carrying client-chosen idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength limits length of idempotency key.
+const maxIdempotencyKeyLength = 255
+
+// Idempotency creates middleware replaying the first response to mutating requests
+// with the same Idempotency-Key. Keys are scoped by scope, usually the requesting user.
+// Reusing a key with different request gets 422, server errors are not stored.
+func Idempotency(store *idempotency.Store, scope func(r *http.Request, body []byte) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := sha256.New()
+			io.WriteString(fingerprint, r.Method+" "+r.URL.RequestURI()+"\n"+r.Header.Get("Content-Type")+"\n")
+			fingerprint.Write(body)
+
+			response, finish, err := store.Begin(r.Context(), scope(r, body)+"\x00"+key,
+				hex.EncodeToString(fingerprint.Sum(nil)))
+			if err == idempotency.ErrMismatch {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+			if err != nil {
+				http.Error(w, "request cancelled", http.StatusServiceUnavailable)
+				return
+			}
+			if response != nil {
+				for name, values := range response.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(response.Status)
+				w.Write(response.Body)
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed || rec.status >= http.StatusInternalServerError {
+					finish(nil)
+					return
+				}
+				finish(&idempotency.Response{
+					Status: rec.status,
+					Header: w.Header().Clone(),
+					Body:   rec.body.Bytes(),
+				})
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+		})
+	}
+}
+
+// isMutating tells whether method changes state.
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordingWriter passes response through and keeps a copy of it.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader writes header of a response.
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.status = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes body of a response.
+func (rw *recordingWriter) Write(data []byte) (int, error) {
+	rw.body.Write(data)
+	return rw.ResponseWriter.Write(data)
+}
@@ -45,6 +45,23 @@ func LoggingMiddleware(filename string) func(http.Handler) http.Handler {
 	}
 }
 
+// MaxBodySize creates middleware limiting request body to limit bytes.
+// Requests declaring larger Content-Length are rejected with 413 right away,
+// others fail with 413 when the handler reads past the limit.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, fmt.Sprintf("request body too large: limit is %d bytes", limit),
+					http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
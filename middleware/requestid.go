@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is header carrying request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits length of client-provided request ID.
+const maxRequestIDLength = 128
+
+// RequestID creates middleware giving every request an ID, kept from the client when provided.
+// The ID is set on request and response headers.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newRequestID returns random 128-bit hex ID.
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
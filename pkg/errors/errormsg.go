@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ValidationError 400 error.
 type ValidationError struct {
@@ -34,3 +37,58 @@ type InternalError struct {
 func (e InternalError) Error() string {
 	return fmt.Sprintf("internal error: %s - %s", e.Operation, e.Message)
 }
+
+// ForbiddenError 403 error.
+type ForbiddenError struct {
+	Operation string
+	Message   string
+}
+
+// Error to provide 403 error messages.
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: %s - %s", e.Operation, e.Message)
+}
+
+// ConflictError 409 error.
+type ConflictError struct {
+	Operation string
+	Message   string
+	Details   interface{}
+}
+
+// Error to provide 409 error messages.
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("conflict error: %s - %s", e.Operation, e.Message)
+}
+
+// PreconditionFailedError 412 error.
+type PreconditionFailedError struct {
+	Operation string
+	Message   string
+}
+
+// Error to provide 412 error messages.
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("precondition failed: %s - %s", e.Operation, e.Message)
+}
+
+// UnsupportedMediaTypeError 415 error.
+type UnsupportedMediaTypeError struct {
+	MediaType string
+	Supported []string
+}
+
+// Error to provide 415 error messages.
+func (e UnsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("unsupported media type: %q, use one of %s", e.MediaType, strings.Join(e.Supported, ", "))
+}
+
+// PayloadTooLargeError 413 error.
+type PayloadTooLargeError struct {
+	Limit int64
+}
+
+// Error to provide 413 error messages.
+func (e PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("request body too large: limit is %d bytes", e.Limit)
+}